@@ -0,0 +1,36 @@
+package hpp
+
+import "strings"
+
+// PaymentMethod is an alternative payment method offered in PM_METHODS and returned in PAYMENTMETHOD
+type PaymentMethod string
+
+// Payment methods
+const (
+	PaymentMethodCards   PaymentMethod = "cards"
+	PaymentMethodPayPal  PaymentMethod = "paypal"
+	PaymentMethodSofort  PaymentMethod = "sofort"
+	PaymentMethodIDEAL   PaymentMethod = "ideal"
+	PaymentMethodGiropay PaymentMethod = "giropay"
+)
+
+// SetPaymentMethods sets the payment methods offered on HPP
+func (r *Request) SetPaymentMethods(methods ...PaymentMethod) {
+	s := make([]string, len(methods))
+	for i, m := range methods {
+		s[i] = string(m)
+	}
+
+	r.PaymentMethods = strings.Join(s, "|")
+}
+
+// Pending is true for alternative payment methods whose outcome will be confirmed later,
+// by a request to the HPP_TX_STATUS_URL
+func (r *Response) Pending() bool {
+	return r.ResultCode().IsPending()
+}
+
+// Final is true if the outcome of the transaction will not change
+func (r *Response) Final() bool {
+	return r.ResultCode().IsFinal()
+}
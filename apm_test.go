@@ -0,0 +1,135 @@
+package hpp
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAPM(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		request     Request
+
+		//expected
+		err error
+	}{
+		{
+			"Given valid APM attributes",
+			Request{
+				Amount:               1,
+				MerchantID:           "test",
+				PaymentMethods:       "cards|paypal|sofort|ideal",
+				TransactionStatusURL: "https://example.com/hpp/status",
+				MerchantResponseURL:  "https://example.com/hpp/response?brand=one",
+			},
+
+			nil,
+		},
+		{
+			"Given APM attributes that do not match their patterns",
+			Request{
+				Amount:               1,
+				MerchantID:           "test",
+				PaymentMethods:       "cards,paypal",
+				TransactionStatusURL: "ftp://example.com/status",
+				MerchantResponseURL:  "/hpp/response",
+			},
+
+			fmt.Errorf(
+				"HPP_TX_STATUS_URL: %s; MERCHANT_RESPONSE_URL: %s; PM_METHODS: %s",
+				transactionStatusURLPattern,
+				merchantResponseURLPattern,
+				paymentMethodsPattern,
+			),
+		},
+		{
+			"Given APM attributes that are too long",
+			Request{
+				Amount:               1,
+				MerchantID:           "test",
+				PaymentMethods:       randomString(101),
+				TransactionStatusURL: "https://example.com/" + randomString(236),
+			},
+
+			fmt.Errorf("HPP_TX_STATUS_URL: %s; PM_METHODS: %s", transactionStatusURLSize, paymentMethodsSize),
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		err := test.request.Validate()
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.Contains(t, err.Error(), test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+		}
+	}
+}
+
+func TestRequestSetPaymentMethods(t *testing.T) {
+	r := Request{}
+	r.SetPaymentMethods(PaymentMethodCards, PaymentMethodPayPal, PaymentMethodIDEAL)
+
+	assert.Equal(t, "cards|paypal|ideal", r.PaymentMethods)
+}
+
+func TestResponseAPMPending(t *testing.T) {
+	hpp := New("mysecret")
+
+	resp, err := hpp.FromJSON(readSampleResponse("apm-pending"), false)
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, PaymentMethodSofort, resp.PaymentMethod)
+		assert.Equal(t, "ORD453-11 thestore", resp.PaymentPurpose)
+		assert.True(t, resp.Pending())
+		assert.False(t, resp.Final())
+		assert.Equal(t, ResultClassPending, resp.ResultClass())
+		assert.Empty(t, resp.SupplementaryData)
+	}
+
+	assert.True(t, (&Response{Result: "00"}).Final())
+	assert.True(t, (&Response{Result: "101"}).Final())
+	assert.Equal(t, "Transaction pending", ResultPending.Message())
+}
+
+func TestResponseHandlerPending(t *testing.T) {
+	hpp := New("mysecret")
+	failure := func(r *http.Request, resp *Response) Reply { return Reply{HTML: "Failed"} }
+	pending := func(r *http.Request, resp *Response) Reply {
+		return Reply{HTML: template.HTML("Awaiting " + string(resp.PaymentMethod))}
+	}
+
+	var tests = []struct {
+		//given
+		description string
+		config      HandlerConfig
+
+		//expected
+		html string
+	}{
+		{"Given a pending handler", HandlerConfig{OnFailure: failure, OnPending: pending}, "Awaiting sofort"},
+		{"Given no pending handler, the failure handler is used", HandlerConfig{OnFailure: failure}, "Failed"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/hpp/response", strings.NewReader(string(readSampleResponse("apm-pending"))))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Subject
+		NewResponseHandler(&hpp, test.config).ServeHTTP(w, req)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, w.Code, test.description)
+		assert.Equal(t, test.html, w.Body.String(), test.description)
+	}
+}
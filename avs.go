@@ -0,0 +1,92 @@
+package hpp
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	nonDigitRegexp   = regexp.MustCompile(`[^0-9]`)
+	ukPostcodeRegexp = regexp.MustCompile(`^([A-Z]{1,2}[0-9][A-Z0-9]?[0-9][A-Z]{2}|GIR0AA)$`)
+)
+
+// Field limits of the codes built by AVSCode
+const (
+	billingCodeMaxLength  = 60
+	shippingCodeMaxLength = 30
+)
+
+// ukPostcodeCountries use UK-style alphanumeric postcodes (e.g. SW1A 1AA)
+var ukPostcodeCountries = map[string]bool{"GB": true, "GG": true, "IM": true, "JE": true}
+
+// AVSCode builds a BILLING_CODE or SHIPPING_CODE for address verification in the format
+// <digits from postcode>|<digits from address>, e.g. "W1T 5PD" and "12 Main Street" give "15|12".
+// The address digits are taken from the first street line containing any.
+//
+// country is the country of the address, as any code or name LookupCountry accepts.
+// UK postcodes must be valid, and only the 5 digit ZIP of a US ZIP+4 code is used.
+// Codes longer than the 60 characters of BILLING_CODE are rejected.
+func AVSCode(country, postcode string, street ...string) (string, error) {
+	return avsCode(billingCodeMaxLength, country, postcode, street...)
+}
+
+// avsCode builds an AVSCode of at most max characters
+func avsCode(max int, country, postcode string, street ...string) (string, error) {
+	postcode = strings.ToUpper(strings.TrimSpace(postcode))
+
+	c, _ := LookupCountry(country)
+	switch {
+	case ukPostcodeCountries[c.Alpha2]:
+		if !ukPostcodeRegexp.MatchString(strings.Replace(postcode, " ", "", -1)) {
+			return "", errors.Errorf("invalid UK postcode %q", postcode)
+		}
+	case c.Alpha2 == "US":
+		if i := strings.Index(postcode, "-"); i >= 0 {
+			postcode = postcode[:i]
+		}
+	}
+
+	var address string
+	for _, s := range street {
+		if address = digits(s); address != "" {
+			break
+		}
+	}
+
+	code := digits(postcode) + "|" + address
+	if len(code) > max {
+		return "", errors.Errorf("code %q is longer than %d characters", code, max)
+	}
+
+	return code, nil
+}
+
+func digits(s string) string {
+	return nonDigitRegexp.ReplaceAllString(s, "")
+}
+
+// SetBillingCode sets the BILLING_CODE from the billing postcode and street lines, see AVSCode.
+// The country is the BILLING_CO of the request.
+func (r *Request) SetBillingCode(postcode string, street ...string) error {
+	code, err := avsCode(billingCodeMaxLength, r.BillingCountry, postcode, street...)
+	if err != nil {
+		return errors.Wrap(err, "unable to build billing code")
+	}
+
+	r.BillingCode = code
+	return nil
+}
+
+// SetShippingCode sets the SHIPPING_CODE from the shipping postcode and street lines, see AVSCode.
+// The country is the SHIPPING_CO of the request, and the code must fit in its 30 characters.
+func (r *Request) SetShippingCode(postcode string, street ...string) error {
+	code, err := avsCode(shippingCodeMaxLength, r.ShippingCountry, postcode, street...)
+	if err != nil {
+		return errors.Wrap(err, "unable to build shipping code")
+	}
+
+	r.ShippingCode = code
+	return nil
+}
@@ -0,0 +1,70 @@
+package hpp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAVSCode(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		country     string
+		postcode    string
+		street      []string
+
+		//expected
+		code string
+		err  error
+	}{
+		{"Given a UK address", "GB", "W1T 5PD", []string{"12 Main Street"}, "15|12", nil},
+		{"Given a UK postcode without a space", "GB", "ec1a1bb", []string{"Flat 3, 12 High St"}, "11|312", nil},
+		{"Given an invalid UK postcode", "GB", "12345", []string{"12 Main Street"}, "", fmt.Errorf(`invalid UK postcode "12345"`)},
+		{"Given a Jersey address", "JEY", "JE2 3AB", []string{"1 Rue"}, "23|1", nil},
+		{"Given an Irish Eircode", "IE", "D02 X285", []string{"Unit 4", "45 Dame St"}, "02285|4", nil},
+		{"Given a first street line without digits", "IE", "D02 X285", []string{"The Cottage", "45 Dame St"}, "02285|45", nil},
+		{"Given a US ZIP+4 code", "US", "50001-1234", []string{"852 Lake Shore Drive"}, "50001|852", nil},
+		{"Given no street digits", "FR", "75008", []string{"Rue du Faubourg"}, "75008|", nil},
+		{"Given an unknown country", "", "12-345", nil, "12345|", nil},
+		{
+			"Given a code longer than BILLING_CODE",
+			"FR", "75008", []string{strings.Repeat("1", 55)},
+			"", fmt.Errorf(`code "75008|%s" is longer than 60 characters`, strings.Repeat("1", 55)),
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		code, err := AVSCode(test.country, test.postcode, test.street...)
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+			assert.Equal(t, test.code, code, test.description)
+		}
+	}
+}
+
+func TestRequestSetAVSCodes(t *testing.T) {
+	r := Request{Amount: 1, MerchantID: "test", BillingCountry: "GB", ShippingCountry: "US"}
+
+	assert.Nil(t, r.SetBillingCode("W1T 5PD", "12 Main Street"))
+	assert.Nil(t, r.SetShippingCode("50001-1234", "852 Lake Shore Drive"))
+	assert.Equal(t, "15|12", r.BillingCode)
+	assert.Equal(t, "50001|852", r.ShippingCode)
+	assert.Nil(t, r.Validate())
+
+	assert.EqualError(t, r.SetBillingCode("invalid"), `unable to build billing code: invalid UK postcode "INVALID"`)
+	assert.Equal(t, "15|12", r.BillingCode, "the billing code is unchanged")
+
+	street := strings.Repeat("1", 25)
+	assert.Nil(t, r.SetBillingCode("W1T 5PD", street))
+	assert.EqualError(t, r.SetShippingCode("50001", street), `unable to build shipping code: code "50001|`+street+`" is longer than 30 characters`)
+	assert.Equal(t, "50001|852", r.ShippingCode, "the shipping code is unchanged")
+	assert.Nil(t, r.Validate())
+}
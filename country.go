@@ -0,0 +1,325 @@
+package hpp
+
+import "strings"
+
+// Country is an ISO 3166-1 country
+type Country struct {
+	Name    string
+	Alpha2  string
+	Alpha3  string
+	Numeric string
+}
+
+var (
+	countriesByAlpha2  = map[string]Country{}
+	countriesByAlpha3  = map[string]Country{}
+	countriesByNumeric = map[string]Country{}
+	countriesByName    = map[string]Country{}
+)
+
+func init() {
+	for _, c := range countries {
+		countriesByAlpha2[c.Alpha2] = c
+		countriesByAlpha3[c.Alpha3] = c
+		countriesByNumeric[c.Numeric] = c
+		countriesByName[strings.ToUpper(c.Name)] = c
+	}
+}
+
+// CountryByAlpha2 finds a country by its ISO 3166-1 alpha-2 code (e.g. IE)
+func CountryByAlpha2(code string) (Country, bool) {
+	c, ok := countriesByAlpha2[strings.ToUpper(code)]
+	return c, ok
+}
+
+// CountryByAlpha3 finds a country by its ISO 3166-1 alpha-3 code (e.g. IRL)
+func CountryByAlpha3(code string) (Country, bool) {
+	c, ok := countriesByAlpha3[strings.ToUpper(code)]
+	return c, ok
+}
+
+// CountryByNumeric finds a country by its ISO 3166-1 numeric code (e.g. 372)
+func CountryByNumeric(code string) (Country, bool) {
+	c, ok := countriesByNumeric[code]
+	return c, ok
+}
+
+// CountryByName finds a country by its ISO 3166-1 name, ignoring case (e.g. Ireland)
+func CountryByName(name string) (Country, bool) {
+	c, ok := countriesByName[strings.ToUpper(strings.TrimSpace(name))]
+	return c, ok
+}
+
+// LookupCountry finds a country by its alpha-2, alpha-3 or numeric code, or its name
+func LookupCountry(s string) (Country, bool) {
+	for _, lookup := range []func(string) (Country, bool){CountryByAlpha2, CountryByAlpha3, CountryByNumeric, CountryByName} {
+		if c, ok := lookup(s); ok {
+			return c, ok
+		}
+	}
+
+	return Country{}, false
+}
+
+func isAlpha2Country(code string) bool {
+	_, ok := CountryByAlpha2(code)
+	return ok
+}
+
+func isNumericCountry(code string) bool {
+	_, ok := countriesByNumeric[code]
+	return ok
+}
+
+// countries are the ISO 3166-1 countries, ordered by alpha-2 code
+var countries = []Country{
+	{"Andorra", "AD", "AND", "020"},
+	{"United Arab Emirates", "AE", "ARE", "784"},
+	{"Afghanistan", "AF", "AFG", "004"},
+	{"Antigua and Barbuda", "AG", "ATG", "028"},
+	{"Anguilla", "AI", "AIA", "660"},
+	{"Albania", "AL", "ALB", "008"},
+	{"Armenia", "AM", "ARM", "051"},
+	{"Angola", "AO", "AGO", "024"},
+	{"Antarctica", "AQ", "ATA", "010"},
+	{"Argentina", "AR", "ARG", "032"},
+	{"American Samoa", "AS", "ASM", "016"},
+	{"Austria", "AT", "AUT", "040"},
+	{"Australia", "AU", "AUS", "036"},
+	{"Aruba", "AW", "ABW", "533"},
+	{"Åland Islands", "AX", "ALA", "248"},
+	{"Azerbaijan", "AZ", "AZE", "031"},
+	{"Bosnia and Herzegovina", "BA", "BIH", "070"},
+	{"Barbados", "BB", "BRB", "052"},
+	{"Bangladesh", "BD", "BGD", "050"},
+	{"Belgium", "BE", "BEL", "056"},
+	{"Burkina Faso", "BF", "BFA", "854"},
+	{"Bulgaria", "BG", "BGR", "100"},
+	{"Bahrain", "BH", "BHR", "048"},
+	{"Burundi", "BI", "BDI", "108"},
+	{"Benin", "BJ", "BEN", "204"},
+	{"Saint Barthélemy", "BL", "BLM", "652"},
+	{"Bermuda", "BM", "BMU", "060"},
+	{"Brunei Darussalam", "BN", "BRN", "096"},
+	{"Bolivia, Plurinational State of", "BO", "BOL", "068"},
+	{"Bonaire, Sint Eustatius and Saba", "BQ", "BES", "535"},
+	{"Brazil", "BR", "BRA", "076"},
+	{"Bahamas", "BS", "BHS", "044"},
+	{"Bhutan", "BT", "BTN", "064"},
+	{"Bouvet Island", "BV", "BVT", "074"},
+	{"Botswana", "BW", "BWA", "072"},
+	{"Belarus", "BY", "BLR", "112"},
+	{"Belize", "BZ", "BLZ", "084"},
+	{"Canada", "CA", "CAN", "124"},
+	{"Cocos (Keeling) Islands", "CC", "CCK", "166"},
+	{"Congo, The Democratic Republic of the", "CD", "COD", "180"},
+	{"Central African Republic", "CF", "CAF", "140"},
+	{"Congo", "CG", "COG", "178"},
+	{"Switzerland", "CH", "CHE", "756"},
+	{"Côte d'Ivoire", "CI", "CIV", "384"},
+	{"Cook Islands", "CK", "COK", "184"},
+	{"Chile", "CL", "CHL", "152"},
+	{"Cameroon", "CM", "CMR", "120"},
+	{"China", "CN", "CHN", "156"},
+	{"Colombia", "CO", "COL", "170"},
+	{"Costa Rica", "CR", "CRI", "188"},
+	{"Cuba", "CU", "CUB", "192"},
+	{"Cabo Verde", "CV", "CPV", "132"},
+	{"Curaçao", "CW", "CUW", "531"},
+	{"Christmas Island", "CX", "CXR", "162"},
+	{"Cyprus", "CY", "CYP", "196"},
+	{"Czechia", "CZ", "CZE", "203"},
+	{"Germany", "DE", "DEU", "276"},
+	{"Djibouti", "DJ", "DJI", "262"},
+	{"Denmark", "DK", "DNK", "208"},
+	{"Dominica", "DM", "DMA", "212"},
+	{"Dominican Republic", "DO", "DOM", "214"},
+	{"Algeria", "DZ", "DZA", "012"},
+	{"Ecuador", "EC", "ECU", "218"},
+	{"Estonia", "EE", "EST", "233"},
+	{"Egypt", "EG", "EGY", "818"},
+	{"Western Sahara", "EH", "ESH", "732"},
+	{"Eritrea", "ER", "ERI", "232"},
+	{"Spain", "ES", "ESP", "724"},
+	{"Ethiopia", "ET", "ETH", "231"},
+	{"Finland", "FI", "FIN", "246"},
+	{"Fiji", "FJ", "FJI", "242"},
+	{"Falkland Islands (Malvinas)", "FK", "FLK", "238"},
+	{"Micronesia, Federated States of", "FM", "FSM", "583"},
+	{"Faroe Islands", "FO", "FRO", "234"},
+	{"France", "FR", "FRA", "250"},
+	{"Gabon", "GA", "GAB", "266"},
+	{"United Kingdom", "GB", "GBR", "826"},
+	{"Grenada", "GD", "GRD", "308"},
+	{"Georgia", "GE", "GEO", "268"},
+	{"French Guiana", "GF", "GUF", "254"},
+	{"Guernsey", "GG", "GGY", "831"},
+	{"Ghana", "GH", "GHA", "288"},
+	{"Gibraltar", "GI", "GIB", "292"},
+	{"Greenland", "GL", "GRL", "304"},
+	{"Gambia", "GM", "GMB", "270"},
+	{"Guinea", "GN", "GIN", "324"},
+	{"Guadeloupe", "GP", "GLP", "312"},
+	{"Equatorial Guinea", "GQ", "GNQ", "226"},
+	{"Greece", "GR", "GRC", "300"},
+	{"South Georgia and the South Sandwich Islands", "GS", "SGS", "239"},
+	{"Guatemala", "GT", "GTM", "320"},
+	{"Guam", "GU", "GUM", "316"},
+	{"Guinea-Bissau", "GW", "GNB", "624"},
+	{"Guyana", "GY", "GUY", "328"},
+	{"Hong Kong", "HK", "HKG", "344"},
+	{"Heard Island and McDonald Islands", "HM", "HMD", "334"},
+	{"Honduras", "HN", "HND", "340"},
+	{"Croatia", "HR", "HRV", "191"},
+	{"Haiti", "HT", "HTI", "332"},
+	{"Hungary", "HU", "HUN", "348"},
+	{"Indonesia", "ID", "IDN", "360"},
+	{"Ireland", "IE", "IRL", "372"},
+	{"Israel", "IL", "ISR", "376"},
+	{"Isle of Man", "IM", "IMN", "833"},
+	{"India", "IN", "IND", "356"},
+	{"British Indian Ocean Territory", "IO", "IOT", "086"},
+	{"Iraq", "IQ", "IRQ", "368"},
+	{"Iran, Islamic Republic of", "IR", "IRN", "364"},
+	{"Iceland", "IS", "ISL", "352"},
+	{"Italy", "IT", "ITA", "380"},
+	{"Jersey", "JE", "JEY", "832"},
+	{"Jamaica", "JM", "JAM", "388"},
+	{"Jordan", "JO", "JOR", "400"},
+	{"Japan", "JP", "JPN", "392"},
+	{"Kenya", "KE", "KEN", "404"},
+	{"Kyrgyzstan", "KG", "KGZ", "417"},
+	{"Cambodia", "KH", "KHM", "116"},
+	{"Kiribati", "KI", "KIR", "296"},
+	{"Comoros", "KM", "COM", "174"},
+	{"Saint Kitts and Nevis", "KN", "KNA", "659"},
+	{"Korea, Democratic People's Republic of", "KP", "PRK", "408"},
+	{"Korea, Republic of", "KR", "KOR", "410"},
+	{"Kuwait", "KW", "KWT", "414"},
+	{"Cayman Islands", "KY", "CYM", "136"},
+	{"Kazakhstan", "KZ", "KAZ", "398"},
+	{"Lao People's Democratic Republic", "LA", "LAO", "418"},
+	{"Lebanon", "LB", "LBN", "422"},
+	{"Saint Lucia", "LC", "LCA", "662"},
+	{"Liechtenstein", "LI", "LIE", "438"},
+	{"Sri Lanka", "LK", "LKA", "144"},
+	{"Liberia", "LR", "LBR", "430"},
+	{"Lesotho", "LS", "LSO", "426"},
+	{"Lithuania", "LT", "LTU", "440"},
+	{"Luxembourg", "LU", "LUX", "442"},
+	{"Latvia", "LV", "LVA", "428"},
+	{"Libya", "LY", "LBY", "434"},
+	{"Morocco", "MA", "MAR", "504"},
+	{"Monaco", "MC", "MCO", "492"},
+	{"Moldova, Republic of", "MD", "MDA", "498"},
+	{"Montenegro", "ME", "MNE", "499"},
+	{"Saint Martin (French part)", "MF", "MAF", "663"},
+	{"Madagascar", "MG", "MDG", "450"},
+	{"Marshall Islands", "MH", "MHL", "584"},
+	{"North Macedonia", "MK", "MKD", "807"},
+	{"Mali", "ML", "MLI", "466"},
+	{"Myanmar", "MM", "MMR", "104"},
+	{"Mongolia", "MN", "MNG", "496"},
+	{"Macao", "MO", "MAC", "446"},
+	{"Northern Mariana Islands", "MP", "MNP", "580"},
+	{"Martinique", "MQ", "MTQ", "474"},
+	{"Mauritania", "MR", "MRT", "478"},
+	{"Montserrat", "MS", "MSR", "500"},
+	{"Malta", "MT", "MLT", "470"},
+	{"Mauritius", "MU", "MUS", "480"},
+	{"Maldives", "MV", "MDV", "462"},
+	{"Malawi", "MW", "MWI", "454"},
+	{"Mexico", "MX", "MEX", "484"},
+	{"Malaysia", "MY", "MYS", "458"},
+	{"Mozambique", "MZ", "MOZ", "508"},
+	{"Namibia", "NA", "NAM", "516"},
+	{"New Caledonia", "NC", "NCL", "540"},
+	{"Niger", "NE", "NER", "562"},
+	{"Norfolk Island", "NF", "NFK", "574"},
+	{"Nigeria", "NG", "NGA", "566"},
+	{"Nicaragua", "NI", "NIC", "558"},
+	{"Netherlands", "NL", "NLD", "528"},
+	{"Norway", "NO", "NOR", "578"},
+	{"Nepal", "NP", "NPL", "524"},
+	{"Nauru", "NR", "NRU", "520"},
+	{"Niue", "NU", "NIU", "570"},
+	{"New Zealand", "NZ", "NZL", "554"},
+	{"Oman", "OM", "OMN", "512"},
+	{"Panama", "PA", "PAN", "591"},
+	{"Peru", "PE", "PER", "604"},
+	{"French Polynesia", "PF", "PYF", "258"},
+	{"Papua New Guinea", "PG", "PNG", "598"},
+	{"Philippines", "PH", "PHL", "608"},
+	{"Pakistan", "PK", "PAK", "586"},
+	{"Poland", "PL", "POL", "616"},
+	{"Saint Pierre and Miquelon", "PM", "SPM", "666"},
+	{"Pitcairn", "PN", "PCN", "612"},
+	{"Puerto Rico", "PR", "PRI", "630"},
+	{"Palestine, State of", "PS", "PSE", "275"},
+	{"Portugal", "PT", "PRT", "620"},
+	{"Palau", "PW", "PLW", "585"},
+	{"Paraguay", "PY", "PRY", "600"},
+	{"Qatar", "QA", "QAT", "634"},
+	{"Réunion", "RE", "REU", "638"},
+	{"Romania", "RO", "ROU", "642"},
+	{"Serbia", "RS", "SRB", "688"},
+	{"Russian Federation", "RU", "RUS", "643"},
+	{"Rwanda", "RW", "RWA", "646"},
+	{"Saudi Arabia", "SA", "SAU", "682"},
+	{"Solomon Islands", "SB", "SLB", "090"},
+	{"Seychelles", "SC", "SYC", "690"},
+	{"Sudan", "SD", "SDN", "729"},
+	{"Sweden", "SE", "SWE", "752"},
+	{"Singapore", "SG", "SGP", "702"},
+	{"Saint Helena, Ascension and Tristan da Cunha", "SH", "SHN", "654"},
+	{"Slovenia", "SI", "SVN", "705"},
+	{"Svalbard and Jan Mayen", "SJ", "SJM", "744"},
+	{"Slovakia", "SK", "SVK", "703"},
+	{"Sierra Leone", "SL", "SLE", "694"},
+	{"San Marino", "SM", "SMR", "674"},
+	{"Senegal", "SN", "SEN", "686"},
+	{"Somalia", "SO", "SOM", "706"},
+	{"Suriname", "SR", "SUR", "740"},
+	{"South Sudan", "SS", "SSD", "728"},
+	{"Sao Tome and Principe", "ST", "STP", "678"},
+	{"El Salvador", "SV", "SLV", "222"},
+	{"Sint Maarten (Dutch part)", "SX", "SXM", "534"},
+	{"Syrian Arab Republic", "SY", "SYR", "760"},
+	{"Eswatini", "SZ", "SWZ", "748"},
+	{"Turks and Caicos Islands", "TC", "TCA", "796"},
+	{"Chad", "TD", "TCD", "148"},
+	{"French Southern Territories", "TF", "ATF", "260"},
+	{"Togo", "TG", "TGO", "768"},
+	{"Thailand", "TH", "THA", "764"},
+	{"Tajikistan", "TJ", "TJK", "762"},
+	{"Tokelau", "TK", "TKL", "772"},
+	{"Timor-Leste", "TL", "TLS", "626"},
+	{"Turkmenistan", "TM", "TKM", "795"},
+	{"Tunisia", "TN", "TUN", "788"},
+	{"Tonga", "TO", "TON", "776"},
+	{"Türkiye", "TR", "TUR", "792"},
+	{"Trinidad and Tobago", "TT", "TTO", "780"},
+	{"Tuvalu", "TV", "TUV", "798"},
+	{"Taiwan, Province of China", "TW", "TWN", "158"},
+	{"Tanzania, United Republic of", "TZ", "TZA", "834"},
+	{"Ukraine", "UA", "UKR", "804"},
+	{"Uganda", "UG", "UGA", "800"},
+	{"United States Minor Outlying Islands", "UM", "UMI", "581"},
+	{"United States", "US", "USA", "840"},
+	{"Uruguay", "UY", "URY", "858"},
+	{"Uzbekistan", "UZ", "UZB", "860"},
+	{"Holy See (Vatican City State)", "VA", "VAT", "336"},
+	{"Saint Vincent and the Grenadines", "VC", "VCT", "670"},
+	{"Venezuela, Bolivarian Republic of", "VE", "VEN", "862"},
+	{"Virgin Islands, British", "VG", "VGB", "092"},
+	{"Virgin Islands, U.S.", "VI", "VIR", "850"},
+	{"Viet Nam", "VN", "VNM", "704"},
+	{"Vanuatu", "VU", "VUT", "548"},
+	{"Wallis and Futuna", "WF", "WLF", "876"},
+	{"Samoa", "WS", "WSM", "882"},
+	{"Yemen", "YE", "YEM", "887"},
+	{"Mayotte", "YT", "MYT", "175"},
+	{"South Africa", "ZA", "ZAF", "710"},
+	{"Zambia", "ZM", "ZMB", "894"},
+	{"Zimbabwe", "ZW", "ZWE", "716"},
+}
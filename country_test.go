@@ -0,0 +1,48 @@
+package hpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCountry(t *testing.T) {
+	ireland := Country{Name: "Ireland", Alpha2: "IE", Alpha3: "IRL", Numeric: "372"}
+
+	var tests = []struct {
+		//given
+		description string
+		lookup      func(string) (Country, bool)
+		value       string
+
+		//expected
+		country Country
+		ok      bool
+	}{
+		{"Given an alpha-2 code", CountryByAlpha2, "IE", ireland, true},
+		{"Given a lowercase alpha-2 code", CountryByAlpha2, "ie", ireland, true},
+		{"Given an alpha-3 code", CountryByAlpha3, "IRL", ireland, true},
+		{"Given a numeric code", CountryByNumeric, "372", ireland, true},
+		{"Given a name", CountryByName, "IRELAND", ireland, true},
+		{"Given any code", LookupCountry, "irl", ireland, true},
+		{"Given a numeric code with a leading zero", LookupCountry, "076", Country{Name: "Brazil", Alpha2: "BR", Alpha3: "BRA", Numeric: "076"}, true},
+		{"Given an unknown code", LookupCountry, "XX", Country{}, false},
+		{"Given an unknown numeric code", CountryByNumeric, "999", Country{}, false},
+	}
+
+	for _, test := range tests {
+		// Subject
+		country, ok := test.lookup(test.value)
+
+		// Assertions
+		assert.Equal(t, test.ok, ok, test.description)
+		assert.Equal(t, test.country, country, test.description)
+	}
+}
+
+func TestCountries(t *testing.T) {
+	assert.Len(t, countries, 249)
+	assert.Len(t, countriesByAlpha2, len(countries), "alpha-2 codes are unique")
+	assert.Len(t, countriesByAlpha3, len(countries), "alpha-3 codes are unique")
+	assert.Len(t, countriesByNumeric, len(countries), "numeric codes are unique")
+}
@@ -0,0 +1,76 @@
+package hpp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// Clock tells the time of requests without a TIMESTAMP
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a function used as a Clock
+type ClockFunc func() time.Time
+
+// Now calls the function
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// FixedClock is a Clock which always tells the same time, e.g. for golden tests
+type FixedClock time.Time
+
+// Now is the fixed time
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// OrderIDGenerator generates the ORDER_ID of requests without one, given the request TIMESTAMP
+type OrderIDGenerator interface {
+	OrderID(now time.Time) string
+}
+
+// OrderIDFunc is a function used as an OrderIDGenerator
+type OrderIDFunc func(now time.Time) string
+
+// OrderID calls the function
+func (f OrderIDFunc) OrderID(now time.Time) string {
+	return f(now)
+}
+
+// UUIDOrderIDs generates random UUID order IDs, it is used when the HPP has no OrderIDs
+type UUIDOrderIDs struct{}
+
+// OrderID is a new random UUID
+func (UUIDOrderIDs) OrderID(now time.Time) string {
+	return uuid.NewV4().String()
+}
+
+// SequenceOrderIDs generates order IDs from a prefix, the date and a sequence number,
+// e.g. ORD20990101-000001. It is safe for concurrent use.
+type SequenceOrderIDs struct {
+	prefix string
+
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewSequenceOrderIDs builds a SequenceOrderIDs with the prefix, starting at the sequence number
+func NewSequenceOrderIDs(prefix string, start uint64) *SequenceOrderIDs {
+	return &SequenceOrderIDs{prefix: prefix, next: start}
+}
+
+// OrderID is the prefix, date and next sequence number
+func (s *SequenceOrderIDs) OrderID(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("%s%s-%06d", s.prefix, now.Format("20060102"), s.next)
+	s.next++
+
+	return id
+}
@@ -0,0 +1,86 @@
+package hpp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDefaultsWithHPP(t *testing.T) {
+	now := time.Date(2099, 1, 1, 12, 0, 0, 0, time.FixedZone("IST", 3600))
+	hpp := HPP{
+		Clock:    FixedClock(now),
+		OrderIDs: NewSequenceOrderIDs("ORD", 1),
+	}
+
+	first := Request{hpp: &hpp}
+	first.GenerateDefaults()
+	second := Request{hpp: &hpp}
+	second.GenerateDefaults()
+
+	assert.Equal(t, now.UTC(), *first.TimeStamp)
+	assert.Equal(t, time.UTC, first.TimeStamp.Location())
+	assert.Equal(t, "ORD20990101-000001", first.OrderID)
+	assert.Equal(t, "ORD20990101-000002", second.OrderID)
+}
+
+func TestGenerateDefaultsKeepsFields(t *testing.T) {
+	timestamp := time.Date(2013, 8, 14, 12, 22, 39, 0, time.UTC)
+	hpp := HPP{
+		Clock:    ClockFunc(func() time.Time { panic("the clock is not used") }),
+		OrderIDs: OrderIDFunc(func(time.Time) string { panic("the order IDs are not used") }),
+	}
+
+	req := Request{hpp: &hpp, TimeStamp: &timestamp, OrderID: "ORD453-11"}
+	req.GenerateDefaults()
+
+	assert.Equal(t, timestamp, *req.TimeStamp)
+	assert.Equal(t, "ORD453-11", req.OrderID)
+}
+
+func TestSequenceOrderIDsConcurrent(t *testing.T) {
+	ids := NewSequenceOrderIDs("", 0)
+	now := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	seen := make(chan string, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seen <- ids.OrderID(now)
+		}()
+	}
+	wg.Wait()
+	close(seen)
+
+	unique := map[string]bool{}
+	for id := range seen {
+		unique[id] = true
+	}
+	assert.Len(t, unique, 100)
+	assert.Equal(t, "20990101-000100", ids.OrderID(now))
+}
+
+func TestToJSONGolden(t *testing.T) {
+	hpp := HPP{
+		Secret:   "mysecret",
+		Clock:    FixedClock(time.Date(2013, 8, 14, 12, 22, 39, 0, time.UTC)),
+		OrderIDs: OrderIDFunc(func(time.Time) string { return "ORD453-11" }),
+	}
+
+	js, err := hpp.ToJSON(Request{MerchantID: "thestore", Amount: 29900, Currency: "EUR"}, false)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"MERCHANT_ID": "thestore",
+		"ACCOUNT": "",
+		"ORDER_ID": "ORD453-11",
+		"AMOUNT": "29900",
+		"CURRENCY": "EUR",
+		"TIMESTAMP": "20130814122239",
+		"SHA1HASH": "cc72c08e529b3bc153481eda9533b815cef29de3"
+	}`, string(js))
+}
@@ -0,0 +1,97 @@
+package hpp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ResponseField is the form field HPP uses to post the response JSON to the merchant response URL
+const ResponseField = "hppResponse"
+
+// FromRequest produces a Response from the HPP response posted to the merchant response URL.
+// The body may be JSON, form fields or a single hppResponse form field containing the JSON,
+// Base64 encoded values are detected and decoded.
+func (hpp *HPP) FromRequest(req *http.Request) (*Response, error) {
+	data, err := hpp.responseJSON(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response from request")
+	}
+
+	return hpp.FromJSON(data, hpp.isEncoded(data))
+}
+
+func (hpp *HPP) responseJSON(req *http.Request) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		return formJSON(req)
+	}
+
+	if req.Body == nil {
+		return nil, errors.New("request has no body")
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read request body")
+	}
+
+	return data, nil
+}
+
+func formJSON(req *http.Request) ([]byte, error) {
+	var err error
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+		err = req.ParseMultipartForm(32 << 10)
+	} else {
+		err = req.ParseForm()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse form")
+	}
+
+	if resp := req.PostForm.Get(ResponseField); resp != "" {
+		return wrappedJSON(resp)
+	}
+
+	fields := map[string]string{}
+	for k := range req.PostForm {
+		fields[k] = req.PostForm.Get(k)
+	}
+
+	return json.Marshal(fields)
+}
+
+// wrappedJSON reads the hppResponse field which is either JSON or Base64 encoded JSON
+func wrappedJSON(resp string) ([]byte, error) {
+	resp = strings.TrimSpace(resp)
+	if strings.HasPrefix(resp, "{") {
+		return []byte(resp), nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(resp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decode %s", ResponseField)
+	}
+
+	return data, nil
+}
+
+// isEncoded detects Base64 encoded values as the hash is not hexadecimal once encoded
+func (hpp *HPP) isEncoded(data []byte) bool {
+	fields := map[string]interface{}{}
+	if json.Unmarshal(data, &fields) != nil {
+		return false
+	}
+
+	hash, _ := fields[hpp.hashAlgorithm().Key()].(string)
+
+	return hash != "" && !hexadecimalRegexp.MatchString(hash)
+}
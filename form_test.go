@@ -0,0 +1,154 @@
+package hpp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRequest(t *testing.T) {
+	hpp := New("mysecret")
+
+	var tests = []struct {
+		//given
+		description string
+		contentType string
+		body        string
+
+		//expected
+		supplementaryData map[string]interface{}
+		err               error
+	}{
+		{
+			"Given a JSON body",
+			"application/json",
+			string(readSampleResponse("valid")),
+
+			map[string]interface{}{},
+			nil,
+		},
+		{
+			"Given an encoded JSON body",
+			"application/json",
+			string(readSampleResponse("encoded-valid")),
+
+			map[string]interface{}{
+				"UNKNOWN_1": "Unknown value 1",
+				"UNKNOWN_2": "Unknown value 2",
+				"UNKNOWN_3": "Unknown value 3",
+				"UNKNOWN_4": "Unknown value 4",
+			},
+			nil,
+		},
+		{
+			"Given form fields",
+			"application/x-www-form-urlencoded",
+			testResponseForm(false).Encode(),
+
+			map[string]interface{}{"UNKNOWN_1": "Unknown value 1"},
+			nil,
+		},
+		{
+			"Given encoded form fields",
+			"application/x-www-form-urlencoded",
+			testResponseForm(true).Encode(),
+
+			map[string]interface{}{"UNKNOWN_1": "Unknown value 1"},
+			nil,
+		},
+		{
+			"Given an hppResponse field containing JSON",
+			"application/x-www-form-urlencoded; charset=UTF-8",
+			url.Values{ResponseField: {string(readSampleResponse("encoded-valid"))}}.Encode(),
+
+			map[string]interface{}{
+				"UNKNOWN_1": "Unknown value 1",
+				"UNKNOWN_2": "Unknown value 2",
+				"UNKNOWN_3": "Unknown value 3",
+				"UNKNOWN_4": "Unknown value 4",
+			},
+			nil,
+		},
+		{
+			"Given an hppResponse field containing Base64 encoded JSON",
+			"application/x-www-form-urlencoded",
+			url.Values{ResponseField: {base64.StdEncoding.EncodeToString(readSampleResponse("valid"))}}.Encode(),
+
+			map[string]interface{}{},
+			nil,
+		},
+		{
+			"Given an hppResponse field that cannot be decoded",
+			"application/x-www-form-urlencoded",
+			url.Values{ResponseField: {"TEST@"}}.Encode(),
+
+			nil,
+			fmt.Errorf("unable to read response from request: unable to decode hppResponse: illegal base64 data at input byte 4"),
+		},
+		{
+			"Given tampered form fields",
+			"application/x-www-form-urlencoded",
+			url.Values{"ORDER_ID": {"ORD453-11"}, "SHA1HASH": {"f093a0b233daa15f2bf44888f4fe75cb652e7bf0"}}.Encode(),
+
+			nil,
+			fmt.Errorf("unable to build response from json: secret does not match expected: hash mismatch, received f093a0b233daa15f2bf44888f4fe75cb652e7bf0"),
+		},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/response", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", test.contentType)
+
+		// Subject
+		resp, err := hpp.FromRequest(req)
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+			assert.Equal(t, "ORD453-11", resp.OrderID, test.description)
+			assert.Equal(t, "00", resp.Result, test.description)
+			assert.Equal(t, test.supplementaryData, resp.SupplementaryData, test.description)
+		}
+	}
+}
+
+func TestFromRequestTamperedIsHashMismatch(t *testing.T) {
+	hpp := New("mysecret")
+	form := testResponseForm(false)
+	form.Set("RESULT", "101")
+
+	req := httptest.NewRequest(http.MethodPost, "/response", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, err := hpp.FromRequest(req)
+	assert.True(t, errors.Is(err, ErrHashMismatch))
+}
+
+// testResponseForm builds the form HPP posts for the valid sample response
+func testResponseForm(encoded bool) url.Values {
+	fields := map[string]interface{}{}
+	json.Unmarshal(readSampleResponse("valid"), &fields)
+	delete(fields, "TSS")
+	fields["UNKNOWN_1"] = "Unknown value 1"
+
+	form := url.Values{}
+	for k, v := range fields {
+		s := v.(string)
+		if encoded {
+			s = base64.StdEncoding.EncodeToString([]byte(s))
+		}
+		form.Set(k, s)
+	}
+
+	return form
+}
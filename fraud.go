@@ -0,0 +1,83 @@
+package hpp
+
+import (
+	"sort"
+	"strings"
+)
+
+// FraudResult is the outcome of the fraud filter, or one of its rules
+type FraudResult string
+
+// Fraud filter outcomes
+const (
+	FraudPass        FraudResult = "PASS"
+	FraudHold        FraudResult = "HOLD"
+	FraudBlock       FraudResult = "BLOCK"
+	FraudNotExecuted FraudResult = "NOT_EXECUTED"
+)
+
+// FraudRule is the outcome of a fraud filter rule, received as HPP_FRAUDFILTER_RULE_<id>
+// and HPP_FRAUDFILTER_RULE_<id>_NAME
+type FraudRule struct {
+	ID     string
+	Name   string
+	Result FraudResult
+}
+
+const (
+	fraudRulePrefix = "HPP_FRAUDFILTER_RULE_"
+	fraudRuleSuffix = "_NAME"
+)
+
+// takeFraudRules removes the fraud filter rules from the supplementary data, ordered by ID
+func takeFraudRules(extra map[string]interface{}) []FraudRule {
+	byID := map[string]*FraudRule{}
+	rule := func(id string) *FraudRule {
+		if byID[id] == nil {
+			byID[id] = &FraudRule{ID: id}
+		}
+		return byID[id]
+	}
+
+	for k, v := range extra {
+		if !strings.HasPrefix(k, fraudRulePrefix) {
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+
+		id := strings.TrimPrefix(k, fraudRulePrefix)
+		if strings.HasSuffix(id, fraudRuleSuffix) {
+			rule(strings.TrimSuffix(id, fraudRuleSuffix)).Name = s
+		} else {
+			rule(id).Result = FraudResult(s)
+		}
+		delete(extra, k)
+	}
+
+	if len(byID) == 0 {
+		return nil
+	}
+
+	rules := make([]FraudRule, 0, len(byID))
+	for _, r := range byID {
+		rules = append(rules, *r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	return rules
+}
+
+// FraudRule finds the outcome of a fraud filter rule by its ID
+func (r *Response) FraudRule(id string) (FraudRule, bool) {
+	for _, rule := range r.FraudFilterRules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+
+	return FraudRule{}, false
+}
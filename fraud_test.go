@@ -0,0 +1,84 @@
+package hpp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseFraudFilter(t *testing.T) {
+	hpp := New("mysecret")
+
+	resp, err := hpp.FromJSON(readSampleResponse("fraud-filter"), false)
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, FraudHold, resp.FraudFilterResult)
+		assert.Equal(t, []FraudRule{
+			{ID: "56257838-4590-4227-b946-11e061fb15fe", Name: "Cardholder Name Rule", Result: FraudHold},
+			{ID: "cf609cf9-9e5a-4700-ac69-8aa09c119305", Name: "Email Domain Rule", Result: FraudPass},
+		}, resp.FraudFilterRules)
+		assert.Equal(t, map[string]interface{}{"UNKNOWN_1": "Unknown value 1"}, resp.SupplementaryData)
+
+		rule, ok := resp.FraudRule("cf609cf9-9e5a-4700-ac69-8aa09c119305")
+		assert.True(t, ok)
+		assert.Equal(t, "Email Domain Rule", rule.Name)
+
+		_, ok = resp.FraudRule("unknown")
+		assert.False(t, ok)
+	}
+}
+
+func TestResponseFraudFilterEncoded(t *testing.T) {
+	hpp := New("mysecret")
+	js, err := MarshalJSONEncoded(json.RawMessage(readSampleResponse("fraud-filter")), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := hpp.FromJSON(js, true)
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, FraudHold, resp.FraudFilterResult)
+		assert.Len(t, resp.FraudFilterRules, 2)
+	}
+}
+
+func TestResponseFraudFilterTampered(t *testing.T) {
+	hpp := New("mysecret")
+	sample := string(readSampleResponse("fraud-filter"))
+
+	var tests = []struct {
+		//given
+		description string
+		json        string
+	}{
+		{"Given a changed fraud result", strings.Replace(sample, `"HPP_FRAUDFILTER_RESULT":"HOLD"`, `"HPP_FRAUDFILTER_RESULT":"PASS"`, 1)},
+		{"Given a removed fraud result", strings.Replace(sample, `"HPP_FRAUDFILTER_RESULT":"HOLD",`, ``, 1)},
+	}
+
+	for _, test := range tests {
+		// Subject
+		_, err := hpp.FromJSON([]byte(test.json), false)
+
+		// Assertions
+		assert.True(t, errors.Is(err, ErrHashMismatch), test.description)
+	}
+}
+
+func TestResponseBuildHashFraudFilter(t *testing.T) {
+	resp := testResponse()
+	resp.MerchantID = "thestore"
+	resp.OrderID = "ORD453-11"
+	resp.Result = "00"
+	resp.Message = "Successful"
+	resp.PasRef = "3737468273643"
+	resp.AuthCode = "79347"
+
+	assert.Equal(t, "f093a0b233daa15f2bf44888f4fe75cb652e7bf0", resp.BuildHash("mysecret"), "the fraud result is not hashed when absent")
+
+	resp.FraudFilterResult = FraudHold
+	assert.Equal(t, "21fed34ceeebbac89a12bcdf75e7edc598082d67", resp.BuildHash("mysecret"))
+}
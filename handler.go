@@ -0,0 +1,159 @@
+package hpp
+
+import (
+	"html/template"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxBodySize is the largest response body the ResponseHandler accepts by default
+const DefaultMaxBodySize = 64 << 10
+
+// ErrBodyTooLarge is returned when reading a response body larger than the MaxBodySize of the ResponseHandler
+var ErrBodyTooLarge = errors.New("request body too large")
+
+var redirectTemplate = template.Must(template.New("redirect").Parse(`<!DOCTYPE html>
+<html>
+<head><meta http-equiv="refresh" content="0;url={{.}}"></head>
+<body>
+<script>window.top.location.href = {{.}};</script>
+<a href="{{.}}" target="_top">Continue</a>
+</body>
+</html>
+`))
+
+// Reply is written back to HPP, which displays it to the customer
+type Reply struct {
+	// Status code of the reply, defaults to 200.
+	Status int
+
+	// HTML displayed to the customer.
+	HTML template.HTML
+
+	// RedirectURL the customer is sent to, written as a page which redirects the top window.
+	// Takes precedence over HTML.
+	RedirectURL string
+}
+
+// HandlerConfig configures a ResponseHandler
+type HandlerConfig struct {
+	// OnSuccess is called with verified responses with a "00" result.
+	OnSuccess func(r *http.Request, resp *Response) Reply
+
+	// OnFailure is called with verified responses with any other result.
+	OnFailure func(r *http.Request, resp *Response) Reply
+
+	// OnPending is called with verified responses with a pending result, which are confirmed later
+	// at the HPP_TX_STATUS_URL. Pending responses are passed to OnFailure when it is not set.
+	OnPending func(r *http.Request, resp *Response) Reply
+
+	// OnTamper is called when the response hash does not match, or the response is stale or replayed,
+	// defaults to a 400 reply.
+	OnTamper func(r *http.Request, err error) Reply
+
+	// MaxBodySize is the largest body accepted, defaults to DefaultMaxBodySize.
+	MaxBodySize int64
+}
+
+// ResponseHandler is the http.Handler for the merchant response URL.
+// It only accepts POST requests, verifies the response and replies with the result of the callbacks.
+type ResponseHandler struct {
+	hpp    *HPP
+	config HandlerConfig
+}
+
+// NewResponseHandler builds a new ResponseHandler
+func NewResponseHandler(hpp *HPP, config HandlerConfig) *ResponseHandler {
+	return &ResponseHandler{hpp: hpp, config: config}
+}
+
+func (h *ResponseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = &limitedBody{ReadCloser: r.Body, remaining: h.maxBodySize()}
+
+	resp, err := h.hpp.FromRequest(r)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrBodyTooLarge):
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		case isTampered(err) && h.config.OnTamper != nil:
+			h.reply(w, h.config.OnTamper(r, err))
+		default:
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		}
+		return
+	}
+
+	switch {
+	case resp.Pending() && h.config.OnPending != nil:
+		h.reply(w, h.config.OnPending(r, resp))
+	case resp.ResultCode().IsSuccess() && h.config.OnSuccess != nil:
+		h.reply(w, h.config.OnSuccess(r, resp))
+	case !resp.ResultCode().IsSuccess() && h.config.OnFailure != nil:
+		h.reply(w, h.config.OnFailure(r, resp))
+	default:
+		h.reply(w, Reply{})
+	}
+}
+
+func isTampered(err error) bool {
+	return errors.Is(err, ErrHashMismatch) || errors.Is(err, ErrStaleResponse) || errors.Is(err, ErrReplayedResponse)
+}
+
+func (h *ResponseHandler) reply(w http.ResponseWriter, reply Reply) {
+	status := reply.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if reply.RedirectURL != "" {
+		redirectTemplate.Execute(w, reply.RedirectURL)
+		return
+	}
+
+	w.Write([]byte(reply.HTML))
+}
+
+// limitedBody fails with ErrBodyTooLarge once more than the remaining bytes are read
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+
+	// read one byte more than remains to detect bodies which are too large
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+
+	n, b.remaining = int(b.remaining), -1
+	return n, ErrBodyTooLarge
+}
+
+func (h *ResponseHandler) maxBodySize() int64 {
+	if h.config.MaxBodySize > 0 {
+		return h.config.MaxBodySize
+	}
+
+	return DefaultMaxBodySize
+}
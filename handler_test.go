@@ -0,0 +1,165 @@
+package hpp
+
+import (
+	"errors"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseHandler(t *testing.T) {
+	hpp := New("mysecret")
+	config := HandlerConfig{
+		OnSuccess: func(r *http.Request, resp *Response) Reply {
+			return Reply{HTML: template.HTML("Paid " + resp.OrderID)}
+		},
+		OnFailure: func(r *http.Request, resp *Response) Reply {
+			return Reply{RedirectURL: "https://example.com/failed?order=" + resp.OrderID}
+		},
+		OnTamper: func(r *http.Request, err error) Reply {
+			return Reply{Status: http.StatusForbidden, HTML: "Tampered"}
+		},
+		MaxBodySize: 2048,
+	}
+
+	var tests = []struct {
+		//given
+		description string
+		method      string
+		body        string
+
+		//expected
+		status int
+		html   string
+	}{
+		{
+			"Given a successful response",
+			http.MethodPost,
+			testResponseForm(false).Encode(),
+
+			http.StatusOK,
+			"Paid ORD453-11",
+		},
+		{
+			"Given a tampered response",
+			http.MethodPost,
+			strings.Replace(testResponseForm(false).Encode(), "RESULT=00", "RESULT=101", 1),
+
+			http.StatusForbidden,
+			"Tampered",
+		},
+		{
+			"Given a GET request",
+			http.MethodGet,
+			"",
+
+			http.StatusMethodNotAllowed,
+			"Method Not Allowed\n",
+		},
+		{
+			"Given a body that is too large",
+			http.MethodPost,
+			testResponseForm(false).Encode() + "&PADDING=" + strings.Repeat("A", 2048),
+
+			http.StatusRequestEntityTooLarge,
+			"Request Entity Too Large\n",
+		},
+		{
+			"Given a malformed body",
+			http.MethodPost,
+			"%%%",
+
+			http.StatusBadRequest,
+			"Bad Request\n",
+		},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/response", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		// Subject
+		NewResponseHandler(&hpp, config).ServeHTTP(w, req)
+
+		// Assertions
+		assert.Equal(t, test.status, w.Code, test.description)
+		assert.Equal(t, test.html, w.Body.String(), test.description)
+	}
+}
+
+func TestResponseHandlerRedirect(t *testing.T) {
+	hpp := New("mysecret")
+
+	handler := NewResponseHandler(&hpp, HandlerConfig{
+		OnFailure: func(r *http.Request, resp *Response) Reply {
+			return Reply{RedirectURL: `https://example.com/failed?order=<script>`}
+		},
+	})
+
+	form := testResponseForm(false)
+	form.Set("RESULT", "101")
+	r := Response{
+		TimeStamp:  testResponse().TimeStamp,
+		MerchantID: form.Get("MERCHANT_ID"),
+		OrderID:    form.Get("ORDER_ID"),
+		Result:     "101",
+		Message:    form.Get("MESSAGE"),
+		PasRef:     form.Get("PASREF"),
+		AuthCode:   form.Get("AUTHCODE"),
+	}
+	form.Set("SHA1HASH", r.BuildHash("mysecret"))
+
+	req := httptest.NewRequest(http.MethodPost, "/response", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `window.top.location.href = "https://example.com/failed?order=\u003cscript\u003e"`)
+	assert.NotContains(t, w.Body.String(), "order=<script>")
+}
+
+func TestResponseHandlerMaxBodySizeJSON(t *testing.T) {
+	hpp := New("mysecret")
+	body := string(readSampleResponse("valid"))
+
+	var tests = []struct {
+		//given
+		description string
+		maxBodySize int64
+
+		//expected
+		status int
+	}{
+		{"Given a body the size of the limit", int64(len(body)), http.StatusOK},
+		{"Given a body one byte over the limit", int64(len(body)) - 1, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/response", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Subject
+		NewResponseHandler(&hpp, HandlerConfig{MaxBodySize: test.maxBodySize}).ServeHTTP(w, req)
+
+		// Assertions
+		assert.Equal(t, test.status, w.Code, test.description)
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	b := &limitedBody{ReadCloser: ioutil.NopCloser(strings.NewReader("abcdef")), remaining: 4}
+
+	data, err := ioutil.ReadAll(b)
+
+	assert.Equal(t, "abcd", string(data))
+	assert.True(t, errors.Is(err, ErrBodyTooLarge))
+}
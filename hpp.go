@@ -2,19 +2,100 @@ package hpp
 
 import (
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-// Separator used in generating sha1 hashes
+// Separator used in generating hashes
 const Separator = "."
 
+// HashAlgorithm is the digest used to sign requests and verify responses
+type HashAlgorithm int
+
+const (
+	// SHA1 hashes are sent and received in the SHA1HASH field (the default)
+	SHA1 HashAlgorithm = iota
+
+	// SHA256 hashes are sent and received in the SHA256HASH field
+	SHA256
+)
+
+// Key is the JSON field name the hash is sent / received in
+func (a HashAlgorithm) Key() string {
+	if a == SHA256 {
+		return "SHA256HASH"
+	}
+
+	return "SHA1HASH"
+}
+
+// Size is the length of the hex encoded hash
+func (a HashAlgorithm) Size() int {
+	if a == SHA256 {
+		return 64
+	}
+
+	return 40
+}
+
+// GenerateHash generates the hash using the algorithm, see GenerateHash for the procedure
+func (a HashAlgorithm) GenerateHash(str, secret string) string {
+	firstHashStr := a.sum([]byte(str))
+
+	//second pass takes the first hash, adds the secret and hashes again
+	firstWithSecret := strings.Join([]string{firstHashStr, secret}, Separator)
+
+	return a.sum([]byte(firstWithSecret))
+}
+
+func (a HashAlgorithm) sum(data []byte) string {
+	if a == SHA256 {
+		return fmt.Sprintf("%x", sha256.Sum256(data))
+	}
+
+	return fmt.Sprintf("%x", sha1.Sum(data))
+}
+
 // HPP container that we pass to requests / responses
 type HPP struct {
+	// Secret is the shared secret used to sign requests and verify responses
 	Secret string
+
+	// SecondarySecrets are also accepted when verifying responses, e.g. while rotating the shared secret
+	SecondarySecrets []string
+
+	// Algorithm used to sign requests and verify responses, defaults to SHA1
+	Algorithm HashAlgorithm
+
+	// Logger receives messages as requests are built and responses verified, defaults to NopLogger
+	Logger Logger
+
+	// Language validation messages are reported in (e.g. ES), defaults to the HPP_LANG of the request.
+	// Messages are in English when there is no translation for the language, see RegisterMessages.
+	Language string
+
+	// Sanitise the free-text fields of requests before they are validated, see Request.Sanitise.
+	Sanitise bool
+
+	// Clock tells the time of requests without a TIMESTAMP, defaults to the system clock.
+	Clock Clock
+
+	// OrderIDs generates the ORDER_ID of requests without one, defaults to UUIDOrderIDs.
+	OrderIDs OrderIDGenerator
+
+	// MaxResponseAge rejects responses whose TIMESTAMP is further than this from the Clock, 0 accepts any TIMESTAMP.
+	MaxResponseAge time.Duration
+
+	// SeenStore records verified responses so each ORDER_ID and PASREF is only accepted once, nil accepts replays.
+	SeenStore SeenStore
+
+	// RiskPolicy decides whether to accept, review or reject verified responses, defaults to DefaultRiskPolicy.
+	RiskPolicy *RiskPolicy
 }
 
 // New builds a new HPP
@@ -38,6 +119,56 @@ func (hpp *HPP) FromJSON(data []byte, encoded bool) (*Response, error) {
 	return &resp, nil
 }
 
+// secrets returns the primary secret followed by any secondary secrets
+func (hpp *HPP) secrets() []string {
+	return append([]string{hpp.Secret}, hpp.SecondarySecrets...)
+}
+
+func (hpp *HPP) logger() Logger {
+	if hpp == nil || hpp.Logger == nil {
+		return NopLogger{}
+	}
+
+	return hpp.Logger
+}
+
+// messageLanguage is the language validation messages for a request are reported in
+func (hpp *HPP) messageLanguage(requestLang string) string {
+	if hpp == nil || hpp.Language == "" {
+		return requestLang
+	}
+
+	return hpp.Language
+}
+
+func (hpp *HPP) now() time.Time {
+	if hpp == nil || hpp.Clock == nil {
+		return time.Now()
+	}
+
+	return hpp.Clock.Now()
+}
+
+func (hpp *HPP) orderIDs() OrderIDGenerator {
+	if hpp == nil || hpp.OrderIDs == nil {
+		return UUIDOrderIDs{}
+	}
+
+	return hpp.OrderIDs
+}
+
+func (hpp *HPP) sanitise() bool {
+	return hpp != nil && hpp.Sanitise
+}
+
+func (hpp *HPP) hashAlgorithm() HashAlgorithm {
+	if hpp == nil {
+		return SHA1
+	}
+
+	return hpp.Algorithm
+}
+
 // GenerateHash ...
 // Each message sent to Realex should have a hash, attached. For a message using the remote
 // interface this is generated using the This is generated from the TIMESTAMP, MERCHANT_ID,
@@ -79,14 +210,7 @@ func (hpp *HPP) FromJSON(data []byte, encoded bool) (*Response, error) {
 // (3c3cac74f2b783598b99af6e43246529346d95d1)
 //
 // This method takes the pre-built string of concatenated fields and the secret and returns the
-// SHA-1 hash to be placed in the request sent to Realex.
+// SHA-1 hash to be placed in the request sent to Realex. Use SHA256.GenerateHash for SHA-256.
 func GenerateHash(str, secret string) string {
-	firstHash := sha1.Sum([]byte(str))
-	firstHashStr := fmt.Sprintf("%x", firstHash)
-
-	//second pass takes the first hash, adds the secret and hashes again
-	firstWithSecret := strings.Join([]string{firstHashStr, secret}, Separator)
-	secondHash := sha1.Sum([]byte(firstWithSecret))
-
-	return fmt.Sprintf("%x", secondHash)
+	return SHA1.GenerateHash(str, secret)
 }
@@ -2,6 +2,7 @@ package hpp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -31,9 +32,9 @@ func TestToJSON(t *testing.T) {
 		CommentTwo:        `Comment Two`,
 		ReturnTSS:         "0",
 		ShippingCode:      "56|987",
-		ShippingCountry:   "IRELAND",
+		ShippingCountry:   "IE",
 		BillingCode:       "123|56",
-		BillingCountry:    "IRELAND",
+		BillingCountry:    "IE",
 		CustomerNumber:    "123456",
 		VariableReference: "VariableRef",
 		ProductID:         "ProductID",
@@ -205,7 +206,7 @@ func TestFromJSON(t *testing.T) {
 			true,
 
 			Response{},
-			fmt.Errorf("unable to build response from json: secret does not match expected: expected hash aca0089a38f647d3dae1c1fae9fa0a1c642151f0 received TEST"),
+			fmt.Errorf("unable to build response from json: secret does not match expected: hash mismatch, received TEST"),
 		},
 	}
 
@@ -233,3 +234,72 @@ func TestGenerateHash(t *testing.T) {
 		"generated hash matches expected",
 	)
 }
+
+func TestGenerateHashSHA256(t *testing.T) {
+	hash := SHA256.GenerateHash("test", "secret")
+
+	assert.Equal(
+		t,
+		hash,
+		"42527570dc040bbf9597caf49e364c6bb01af270be490a2532d1f06a3e03ecc1",
+		"generated hash matches expected",
+	)
+}
+
+func TestToJSONSHA256(t *testing.T) {
+	hpp := HPP{Secret: "mysecret", Algorithm: SHA256}
+	req := testRequest(false, false, false)
+
+	js, err := hpp.ToJSON(req, false)
+	assert.Nil(t, err)
+
+	fields := map[string]string{}
+	assert.Nil(t, json.Unmarshal(js, &fields))
+	assert.Equal(t, "1a52904820880cdde83d912641ab010259e480cb6db38ed818bfc856207d90d0", fields["SHA256HASH"])
+	assert.NotContains(t, fields, "SHA1HASH")
+}
+
+func TestFromJSONSHA256(t *testing.T) {
+	hpp := HPP{Secret: "mysecret", Algorithm: SHA256}
+	js := []byte(`{
+		"MERCHANT_ID": "thestore",
+		"ORDER_ID": "ORD453-11",
+		"TIMESTAMP": "20130814122239",
+		"RESULT": "00",
+		"MESSAGE": "Successful",
+		"PASREF": "3737468273643",
+		"AUTHCODE": "79347",
+		"AMOUNT": "100",
+		"SHA256HASH": "d5a24462033b98c7e2303a99b8cfb754bc0f2c9d7d7033a39923fdad927672d0"
+	}`)
+
+	resp, err := hpp.FromJSON(js, false)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "d5a24462033b98c7e2303a99b8cfb754bc0f2c9d7d7033a39923fdad927672d0", resp.Hash)
+		assert.NotContains(t, resp.SupplementaryData, "SHA256HASH")
+	}
+}
+
+func TestFromJSONSecondarySecret(t *testing.T) {
+	hpp := HPP{Secret: "newsecret", SecondarySecrets: []string{"othersecret", "mysecret"}}
+
+	resp, err := hpp.FromJSON(readSampleResponse("valid"), false)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, resp.VerifiedBy, "reports the secondary secret that verified the response")
+	}
+}
+
+func TestFromJSONHashMismatch(t *testing.T) {
+	hpp := New("mysecret")
+
+	_, err := hpp.FromJSON([]byte(`{"ORDER_ID": "ORD453-11", "SHA1HASH": "TEST"}`), false)
+	assert.True(t, errors.Is(err, ErrHashMismatch), "tampered responses match ErrHashMismatch")
+
+	var mismatch *HashMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, "TEST", mismatch.Received)
+	}
+
+	_, err = hpp.FromJSON([]byte(`invalid`), false)
+	assert.False(t, errors.Is(err, ErrHashMismatch), "malformed json does not match ErrHashMismatch")
+}
@@ -0,0 +1,137 @@
+package hpp
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HPP payment page URLs, used as the action of the HTML form
+const (
+	ProductionURL = "https://pay.realexpayments.com/pay"
+	SandboxURL    = "https://pay.sandbox.realexpayments.com/pay"
+)
+
+// FormID is the id of the form rendered by ToForm
+const FormID = "hpp-form"
+
+var formTemplate = template.Must(template.New("form").Parse(`<form id="{{.ID}}" method="POST" action="{{.Action}}">
+{{- range .Fields}}
+<input type="hidden" name="{{.Name}}" value="{{.Value}}">
+{{- end}}
+<noscript><input type="submit" value="{{.SubmitText}}"></noscript>
+</form>
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>document.getElementById("{{.ID}}").submit();</script>
+`))
+
+// FormOptions configure the HTML form rendered by ToForm
+type FormOptions struct {
+	// ActionURL the form posts to, defaults to ProductionURL (or SandboxURL in the sandbox).
+	ActionURL string
+
+	// Sandbox posts the form to SandboxURL.
+	Sandbox bool
+
+	// Encoded Base64 encodes the field values.
+	Encoded bool
+
+	// Nonce is added to the auto-submit script for Content Security Policies.
+	Nonce string
+
+	// SubmitText is displayed on the button shown when JavaScript is disabled, defaults to "Pay Now".
+	SubmitText string
+}
+
+type formField struct {
+	Name  string
+	Value string
+}
+
+// ToForm produces an auto-submitting HTML form that posts a Request to HPP,
+// for integrations that do not use the Realex JS SDK.
+// The request is validated and signed as it is in ToJSON, and the form has the same fields.
+// Number and boolean supplementary data are sent as their JSON text, objects and arrays are rejected.
+func (hpp *HPP) ToForm(req Request, opts FormOptions) (template.HTML, error) {
+	js, err := hpp.ToJSON(req, opts.Encoded)
+	if err != nil {
+		return "", err
+	}
+
+	values := map[string]json.RawMessage{}
+	err = json.Unmarshal(js, &values)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to read request fields")
+	}
+
+	fields := make([]formField, 0, len(values))
+	for k, v := range values {
+		value, err := formValue(v)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read request field %s", k)
+		}
+
+		fields = append(fields, formField{Name: k, Value: value})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+
+	var buf bytes.Buffer
+	err = formTemplate.Execute(&buf, struct {
+		ID         string
+		Action     string
+		Fields     []formField
+		SubmitText string
+		Nonce      string
+	}{
+		FormID,
+		opts.actionURL(),
+		fields,
+		opts.submitText(),
+		opts.Nonce,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "unable to render request form")
+	}
+
+	return template.HTML(buf.String()), nil
+}
+
+// formValue is the form value of a JSON field. Numbers and booleans keep their JSON text,
+// null is empty and objects and arrays, which have no form value, are rejected.
+func formValue(raw json.RawMessage) (string, error) {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, nil
+	}
+
+	switch v := string(bytes.TrimSpace(raw)); {
+	case v == "null":
+		return "", nil
+	case strings.HasPrefix(v, "{"), strings.HasPrefix(v, "["):
+		return "", errors.New("objects and arrays cannot be sent in a form")
+	default:
+		return v, nil
+	}
+}
+
+func (opts FormOptions) actionURL() string {
+	switch {
+	case opts.ActionURL != "":
+		return opts.ActionURL
+	case opts.Sandbox:
+		return SandboxURL
+	}
+
+	return ProductionURL
+}
+
+func (opts FormOptions) submitText() string {
+	if opts.SubmitText != "" {
+		return opts.SubmitText
+	}
+
+	return "Pay Now"
+}
@@ -0,0 +1,145 @@
+package hpp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToForm(t *testing.T) {
+	hpp := New("mysecret")
+
+	var tests = []struct {
+		//given
+		description string
+		request     Request
+		opts        FormOptions
+
+		//expected
+		html string
+		err  error
+	}{
+		{
+			"Given a valid request",
+			testRequest(false, false, false),
+			FormOptions{},
+
+			`<form id="hpp-form" method="POST" action="https://pay.realexpayments.com/pay">
+<input type="hidden" name="ACCOUNT" value="">
+<input type="hidden" name="AMOUNT" value="29900">
+<input type="hidden" name="CURRENCY" value="EUR">
+<input type="hidden" name="MERCHANT_ID" value="thestore">
+<input type="hidden" name="ORDER_ID" value="ORD453-11">
+<input type="hidden" name="SHA1HASH" value="cc72c08e529b3bc153481eda9533b815cef29de3">
+<input type="hidden" name="TIMESTAMP" value="20130814122239">
+<noscript><input type="submit" value="Pay Now"></noscript>
+</form>
+<script>document.getElementById("hpp-form").submit();</script>
+`,
+			nil,
+		},
+		{
+			"Given an encoded sandbox request with a nonce",
+			testRequest(false, false, false),
+			FormOptions{Sandbox: true, Encoded: true, Nonce: "abc123", SubmitText: "Pay"},
+
+			`<form id="hpp-form" method="POST" action="https://pay.sandbox.realexpayments.com/pay">
+<input type="hidden" name="ACCOUNT" value="">
+<input type="hidden" name="AMOUNT" value="Mjk5MDA=">
+<input type="hidden" name="CURRENCY" value="RVVS">
+<input type="hidden" name="MERCHANT_ID" value="dGhlc3RvcmU=">
+<input type="hidden" name="ORDER_ID" value="T1JENDUzLTEx">
+<input type="hidden" name="SHA1HASH" value="Y2M3MmMwOGU1MjliM2JjMTUzNDgxZWRhOTUzM2I4MTVjZWYyOWRlMw==">
+<input type="hidden" name="TIMESTAMP" value="MjAxMzA4MTQxMjIyMzk=">
+<noscript><input type="submit" value="Pay"></noscript>
+</form>
+<script nonce="abc123">document.getElementById("hpp-form").submit();</script>
+`,
+			nil,
+		},
+		{
+			"Given values which need escaping",
+			func() Request {
+				r := testRequest(false, false, false)
+				r.CommentOne = `" onfocus="alert(1)&`
+				return r
+			}(),
+			FormOptions{ActionURL: "https://example.com/pay"},
+
+			`<form id="hpp-form" method="POST" action="https://example.com/pay">
+<input type="hidden" name="ACCOUNT" value="">
+<input type="hidden" name="AMOUNT" value="29900">
+<input type="hidden" name="COMMENT1" value="&#34; onfocus=&#34;alert(1)&amp;">
+<input type="hidden" name="CURRENCY" value="EUR">
+<input type="hidden" name="MERCHANT_ID" value="thestore">
+<input type="hidden" name="ORDER_ID" value="ORD453-11">
+<input type="hidden" name="SHA1HASH" value="cc72c08e529b3bc153481eda9533b815cef29de3">
+<input type="hidden" name="TIMESTAMP" value="20130814122239">
+<noscript><input type="submit" value="Pay Now"></noscript>
+</form>
+<script>document.getElementById("hpp-form").submit();</script>
+`,
+			nil,
+		},
+		{
+			"Given supplementary data which is not a string",
+			func() Request {
+				r := testRequest(false, false, false)
+				r.SupplementaryData = map[string]interface{}{"GIFT": true, "NOTES": nil, "RISK_SCORE": 12.5}
+				return r
+			}(),
+			FormOptions{},
+
+			`<form id="hpp-form" method="POST" action="https://pay.realexpayments.com/pay">
+<input type="hidden" name="ACCOUNT" value="">
+<input type="hidden" name="AMOUNT" value="29900">
+<input type="hidden" name="CURRENCY" value="EUR">
+<input type="hidden" name="GIFT" value="true">
+<input type="hidden" name="MERCHANT_ID" value="thestore">
+<input type="hidden" name="NOTES" value="">
+<input type="hidden" name="ORDER_ID" value="ORD453-11">
+<input type="hidden" name="RISK_SCORE" value="12.5">
+<input type="hidden" name="SHA1HASH" value="cc72c08e529b3bc153481eda9533b815cef29de3">
+<input type="hidden" name="TIMESTAMP" value="20130814122239">
+<noscript><input type="submit" value="Pay Now"></noscript>
+</form>
+<script>document.getElementById("hpp-form").submit();</script>
+`,
+			nil,
+		},
+		{
+			"Given supplementary data with an array",
+			func() Request {
+				r := testRequest(false, false, false)
+				r.SupplementaryData = map[string]interface{}{"BASKET": []string{"ticket"}}
+				return r
+			}(),
+			FormOptions{},
+
+			"",
+			fmt.Errorf("unable to read request field BASKET: objects and arrays cannot be sent in a form"),
+		},
+		{
+			"Given an invalid request",
+			Request{Amount: 100, MerchantID: "test", OrderID: "test%"},
+			FormOptions{},
+
+			"",
+			fmt.Errorf("failed to validate HPP request: ORDER_ID: Order ID must only contain alphanumeric characters, dash and underscore."),
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		html, err := hpp.ToForm(test.request, test.opts)
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+			assert.Equal(t, test.html, string(html), test.description)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package hpp
+
+// Log stages passed in the "stage" field of log messages
+const (
+	StageConvert  = "convert"
+	StageDefaults = "defaults"
+	StageSanitise = "sanitise"
+	StageHash     = "hash"
+	StageValidate = "validate"
+	StageEncode   = "encode"
+	StageDecode   = "decode"
+	StageVerify   = "verify"
+)
+
+// Fields are the structured key / values attached to a log message
+type Fields map[string]interface{}
+
+// Logger receives levelled, structured messages as requests are built and responses verified
+type Logger interface {
+	Debug(msg string, fields Fields)
+	Info(msg string, fields Fields)
+	Warn(msg string, fields Fields)
+	Error(msg string, fields Fields)
+}
+
+// NopLogger discards all log messages, it is used when the HPP has no Logger
+type NopLogger struct{}
+
+// Debug discards the message
+func (NopLogger) Debug(msg string, fields Fields) {}
+
+// Info discards the message
+func (NopLogger) Info(msg string, fields Fields) {}
+
+// Warn discards the message
+func (NopLogger) Warn(msg string, fields Fields) {}
+
+// Error discards the message
+func (NopLogger) Error(msg string, fields Fields) {}
+
+func logFields(merchantID, orderID, stage string) Fields {
+	return Fields{
+		"merchant_id": merchantID,
+		"order_id":    orderID,
+		"stage":       stage,
+	}
+}
+
+func withError(fields Fields, err error) Fields {
+	fields["error"] = err.Error()
+	return fields
+}
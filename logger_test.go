@@ -0,0 +1,95 @@
+package hpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogEntry struct {
+	level  string
+	msg    string
+	fields Fields
+}
+
+type testLogger struct {
+	entries []testLogEntry
+}
+
+func (l *testLogger) Debug(msg string, fields Fields) { l.log("debug", msg, fields) }
+func (l *testLogger) Info(msg string, fields Fields)  { l.log("info", msg, fields) }
+func (l *testLogger) Warn(msg string, fields Fields)  { l.log("warn", msg, fields) }
+func (l *testLogger) Error(msg string, fields Fields) { l.log("error", msg, fields) }
+
+func (l *testLogger) log(level, msg string, fields Fields) {
+	l.entries = append(l.entries, testLogEntry{level, msg, fields})
+}
+
+func TestLoggerToJSON(t *testing.T) {
+	logger := &testLogger{}
+	hpp := HPP{Secret: "mysecret", Logger: logger}
+
+	_, err := hpp.ToJSON(testRequest(false, false, false), true)
+	assert.Nil(t, err)
+
+	var stages []string
+	for _, e := range logger.entries {
+		assert.Equal(t, "thestore", e.fields["merchant_id"])
+		assert.Equal(t, "ORD453-11", e.fields["order_id"])
+		stages = append(stages, e.fields["stage"].(string))
+	}
+
+	assert.Equal(t, []string{StageConvert, StageDefaults, StageHash, StageValidate, StageEncode}, stages)
+}
+
+func TestLoggerFromJSON(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		json        []byte
+
+		//expected
+		level string
+		msg   string
+	}{
+		{
+			"Given a valid response, it is logged as verified",
+			readSampleResponse("valid"),
+
+			"info",
+			"Response verified.",
+		},
+		{
+			"Given a tampered response, the mismatch is logged",
+			[]byte(`{"ORDER_ID": "ORD453-11", "SHA1HASH": "TEST"}`),
+
+			"warn",
+			"Response hash does not match.",
+		},
+		{
+			"Given invalid json, the decode error is logged",
+			[]byte(`invalid`),
+
+			"error",
+			"Unable to decode response.",
+		},
+	}
+
+	for _, test := range tests {
+		logger := &testLogger{}
+		hpp := HPP{Secret: "mysecret", Logger: logger}
+
+		hpp.FromJSON(test.json, false)
+
+		last := logger.entries[len(logger.entries)-1]
+		assert.Equal(t, test.level, last.level, test.description)
+		assert.Equal(t, test.msg, last.msg, test.description)
+	}
+}
+
+func TestNopLogger(t *testing.T) {
+	hpp := New("mysecret")
+
+	assert.Equal(t, NopLogger{}, hpp.logger())
+	assert.Equal(t, NopLogger{}, (*HPP)(nil).logger())
+}
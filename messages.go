@@ -0,0 +1,118 @@
+package hpp
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Messages translate validation messages, keyed by rule code (e.g. too_long) or by JSON key and
+// rule code (e.g. ORDER_ID.too_long) to override the message of a single field.
+// {field} and {limit} in a message are replaced by the JSON key and the limit of the rule.
+type Messages map[string]string
+
+// message finds the translation of a field error, preferring the field's own message
+func (m Messages) message(f FieldError) (string, bool) {
+	msg, ok := m[f.Key+"."+f.Code]
+	if !ok {
+		msg, ok = m[f.Code]
+	}
+	if !ok {
+		return "", false
+	}
+
+	return strings.NewReplacer("{field}", f.Key, "{limit}", strconv.Itoa(f.Limit)).Replace(msg), true
+}
+
+var (
+	catalogueMu sync.RWMutex
+
+	// catalogue holds the messages for each HPP_LANG language, English messages are the constants in validation.go
+	catalogue = map[string]Messages{
+		"DE": {
+			CodeRequired:   "{field} ist erforderlich",
+			CodeTooShort:   "{field} muss mindestens {limit} Zeichen lang sein",
+			CodeTooLong:    "{field} darf nicht mehr als {limit} Zeichen lang sein",
+			CodeTooSmall:   "{field} muss mindestens {limit} sein",
+			CodeTooLarge:   "{field} darf nicht größer als {limit} sein",
+			CodeBadPattern: "{field} enthält ungültige Zeichen",
+			CodeUnknown:    "{field} ist kein bekannter Code",
+			CodeNotAllowed: "{field} ist kein zulässiger Wert",
+		},
+		"ES": {
+			CodeRequired:   "{field} es obligatorio",
+			CodeTooShort:   "{field} debe tener al menos {limit} caracteres",
+			CodeTooLong:    "{field} no debe tener más de {limit} caracteres",
+			CodeTooSmall:   "{field} debe ser al menos {limit}",
+			CodeTooLarge:   "{field} no debe ser mayor que {limit}",
+			CodeBadPattern: "{field} contiene caracteres no válidos",
+			CodeUnknown:    "{field} no es un código reconocido",
+			CodeNotAllowed: "{field} no es un valor permitido",
+		},
+		"FR": {
+			CodeRequired:   "{field} est obligatoire",
+			CodeTooShort:   "{field} doit contenir au moins {limit} caractères",
+			CodeTooLong:    "{field} ne doit pas dépasser {limit} caractères",
+			CodeTooSmall:   "{field} doit être au moins {limit}",
+			CodeTooLarge:   "{field} ne doit pas dépasser {limit}",
+			CodeBadPattern: "{field} contient des caractères non valides",
+			CodeUnknown:    "{field} n'est pas un code reconnu",
+			CodeNotAllowed: "{field} n'est pas une valeur autorisée",
+		},
+		"IT": {
+			CodeRequired:   "{field} è obbligatorio",
+			CodeTooShort:   "{field} deve contenere almeno {limit} caratteri",
+			CodeTooLong:    "{field} non deve superare {limit} caratteri",
+			CodeTooSmall:   "{field} deve essere almeno {limit}",
+			CodeTooLarge:   "{field} non deve essere maggiore di {limit}",
+			CodeBadPattern: "{field} contiene caratteri non validi",
+			CodeUnknown:    "{field} non è un codice riconosciuto",
+			CodeNotAllowed: "{field} non è un valore consentito",
+		},
+	}
+)
+
+// LookupMessages finds the messages for an HPP_LANG language (e.g. ES), ignoring case
+func LookupMessages(lang string) (Messages, bool) {
+	catalogueMu.RLock()
+	defer catalogueMu.RUnlock()
+
+	m, ok := catalogue[strings.ToUpper(lang)]
+	return m, ok
+}
+
+// RegisterMessages adds or replaces the messages for an HPP_LANG language
+func RegisterMessages(lang string, messages Messages) {
+	catalogueMu.Lock()
+	defer catalogueMu.Unlock()
+
+	catalogue[strings.ToUpper(lang)] = messages
+}
+
+// Localise translates the messages into an HPP_LANG language. Messages without a
+// translation, and all messages for languages without any, are left in English.
+func (e *ValidationError) Localise(lang string) *ValidationError {
+	m, ok := LookupMessages(lang)
+	if !ok {
+		return e
+	}
+
+	le := &ValidationError{Fields: make([]FieldError, len(e.Fields))}
+	for i, f := range e.Fields {
+		if msg, ok := m.message(f); ok {
+			f.Message = msg
+		}
+		le.Fields[i] = f
+	}
+
+	return le
+}
+
+// localise translates validation errors into the language, any other error is returned as is
+func localise(err error, lang string) error {
+	if ve, ok := err.(*ValidationError); ok {
+		return ve.Localise(lang)
+	}
+
+	return err
+}
@@ -0,0 +1,84 @@
+package hpp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationErrorLocalise(t *testing.T) {
+	err := validationError(Request{Amount: 1, MerchantID: "test%", OrderID: randomString(51)})
+
+	var tests = []struct {
+		//given
+		description string
+		lang        string
+
+		//expected
+		messages []string
+	}{
+		{"Given Spanish", "ES", []string{"MERCHANT_ID contiene caracteres no válidos", "ORDER_ID no debe tener más de 50 caracteres"}},
+		{"Given lowercase German", "de", []string{"MERCHANT_ID enthält ungültige Zeichen", "ORDER_ID darf nicht mehr als 50 Zeichen lang sein"}},
+		{"Given English", "EN", []string{merchantIDPattern, orderIDSize}},
+		{"Given a language without translations", "XX", []string{merchantIDPattern, orderIDSize}},
+	}
+
+	for _, test := range tests {
+		// Subject
+		le := err.Localise(test.lang)
+
+		// Assertions
+		var messages []string
+		for _, f := range le.Fields {
+			messages = append(messages, f.Message)
+		}
+		assert.Equal(t, test.messages, messages, test.description)
+	}
+
+	assert.Equal(t, merchantIDPattern, err.Fields[0].Message, "the original messages are unchanged")
+}
+
+func TestRegisterMessages(t *testing.T) {
+	RegisterMessages("nl", Messages{
+		CodeRequired:                  "{field} is verplicht",
+		"MERCHANT_ID." + CodeRequired: "Vul uw merchant ID in",
+	})
+	defer func() {
+		catalogueMu.Lock()
+		delete(catalogue, "NL")
+		catalogueMu.Unlock()
+	}()
+
+	le := validationError(Request{}).Localise("NL")
+
+	assert.Equal(t, "AMOUNT is verplicht", le.Fields[0].Message)
+	assert.Equal(t, "Vul uw merchant ID in", le.Fields[1].Message, "field messages override the rule message")
+}
+
+func TestToJSONLocalisedValidationError(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		hpp         HPP
+		request     Request
+
+		//expected
+		message string
+	}{
+		{"Given the request language", HPP{Secret: "mysecret"}, Request{MerchantID: "test", Language: "FR"}, "AMOUNT est obligatoire"},
+		{"Given the HPP language", HPP{Secret: "mysecret", Language: "IT"}, Request{MerchantID: "test", Language: "FR"}, "AMOUNT è obbligatorio"},
+		{"Given no language", HPP{Secret: "mysecret"}, Request{MerchantID: "test"}, requiredMessage},
+	}
+
+	for _, test := range tests {
+		// Subject
+		_, err := test.hpp.ToJSON(test.request, false)
+
+		// Assertions
+		var ve *ValidationError
+		if assert.True(t, errors.As(err, &ve), test.description) {
+			assert.Equal(t, test.message, ve.Fields[0].Message, test.description)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package hpp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SettleCeilingPercent is the percentage of the authorised amount a transaction can be settled for
+const SettleCeilingPercent = 115
+
+// Money is an amount in the lowest unit of an ISO 4217 currency, i.e. 10000 EUR is 100.00 euro
+// and 100 JPY is 100 yen.
+type Money struct {
+	Amount   int
+	Currency string
+}
+
+// ParseMoney parses a decimal amount (e.g. "100.00") in a currency into its lowest unit.
+// Amounts with more decimal places than the currency has are rejected rather than rounded.
+func ParseMoney(amount, currency string) (Money, error) {
+	c, ok := LookupCurrency(currency)
+	if !ok {
+		return Money{}, errors.Errorf("unknown currency %q", currency)
+	}
+
+	whole, frac := strings.TrimSpace(amount), ""
+	if i := strings.Index(whole, "."); i >= 0 {
+		whole, frac = whole[:i], whole[i+1:]
+	}
+
+	if whole == "" || !numericRegexp.MatchString(whole) || !numericRegexp.MatchString(frac) {
+		return Money{}, errors.Errorf("invalid amount %q", amount)
+	}
+
+	if len(frac) > c.MinorUnits {
+		return Money{}, errors.Errorf("amount %q has more than %d decimal places for %s", amount, c.MinorUnits, c.Code)
+	}
+
+	frac += strings.Repeat("0", c.MinorUnits-len(frac))
+
+	n, err := strconv.Atoi(whole + frac)
+	if err != nil {
+		return Money{}, errors.Wrapf(err, "invalid amount %q", amount)
+	}
+
+	return Money{Amount: n, Currency: c.Code}, nil
+}
+
+// String formats the amount as a decimal in the major unit of the currency, e.g. "100.00"
+func (m Money) String() string {
+	units := 0
+	if c, ok := LookupCurrency(m.Currency); ok {
+		units = c.MinorUnits
+	}
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	s := strconv.Itoa(amount)
+	if units == 0 {
+		return sign + s
+	}
+
+	if len(s) <= units {
+		s = strings.Repeat("0", units-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-units] + "." + s[len(s)-units:]
+}
+
+// SettleCeiling is the most the transaction can be settled for, 115% of the amount rounded down
+func (m Money) SettleCeiling() Money {
+	return Money{Amount: m.Amount * SettleCeilingPercent / 100, Currency: m.Currency}
+}
+
+// Money is the amount and currency of the request
+func (r *Request) Money() Money {
+	return Money{Amount: r.Amount, Currency: r.Currency}
+}
+
+// SetMoney sets the amount and currency of the request
+func (r *Request) SetMoney(m Money) {
+	r.Amount = m.Amount
+	r.Currency = m.Currency
+}
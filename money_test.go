@@ -0,0 +1,78 @@
+package hpp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		amount      string
+		currency    string
+
+		//expected
+		money Money
+		err   error
+	}{
+		{"Given a decimal amount", "100.00", "EUR", Money{10000, "EUR"}, nil},
+		{"Given a whole amount", "100", "EUR", Money{10000, "EUR"}, nil},
+		{"Given a single decimal place", "0.5", "GBP", Money{50, "GBP"}, nil},
+		{"Given a trailing decimal point", "12.", "EUR", Money{1200, "EUR"}, nil},
+		{"Given a zero decimal currency", "1500", "JPY", Money{1500, "JPY"}, nil},
+		{"Given a three decimal currency", "1.234", "KWD", Money{1234, "KWD"}, nil},
+		{"Given too many decimal places", "1.234", "EUR", Money{}, fmt.Errorf(`amount "1.234" has more than 2 decimal places for EUR`)},
+		{"Given decimal places for a zero decimal currency", "1500.00", "JPY", Money{}, fmt.Errorf(`amount "1500.00" has more than 0 decimal places for JPY`)},
+		{"Given a negative amount", "-1.00", "EUR", Money{}, fmt.Errorf(`invalid amount "-1.00"`)},
+		{"Given an empty amount", "", "EUR", Money{}, fmt.Errorf(`invalid amount ""`)},
+		{"Given a float exponent", "1e3", "EUR", Money{}, fmt.Errorf(`invalid amount "1e3"`)},
+		{"Given an unknown currency", "1.00", "EURO", Money{}, fmt.Errorf(`unknown currency "EURO"`)},
+	}
+
+	for _, test := range tests {
+		// Subject
+		m, err := ParseMoney(test.amount, test.currency)
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+			assert.Equal(t, test.money, m, test.description)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	assert.Equal(t, "100.00", Money{10000, "EUR"}.String())
+	assert.Equal(t, "0.05", Money{5, "EUR"}.String())
+	assert.Equal(t, "0.00", Money{0, "EUR"}.String())
+	assert.Equal(t, "1500", Money{1500, "JPY"}.String())
+	assert.Equal(t, "1.234", Money{1234, "KWD"}.String())
+	assert.Equal(t, "-0.05", Money{-5, "EUR"}.String())
+	assert.Equal(t, "-100.00", Money{-10000, "EUR"}.String())
+	assert.Equal(t, "-1500", Money{-1500, "JPY"}.String())
+	assert.Equal(t, "-0.001", Money{-1, "KWD"}.String())
+}
+
+func TestMoneySettleCeiling(t *testing.T) {
+	assert.Equal(t, Money{11500, "EUR"}, Money{10000, "EUR"}.SettleCeiling())
+	assert.Equal(t, Money{34385, "EUR"}, Money{29900, "EUR"}.SettleCeiling(), "rounds down")
+	assert.Equal(t, Money{1, "JPY"}, Money{1, "JPY"}.SettleCeiling())
+}
+
+func TestRequestMoney(t *testing.T) {
+	r := Request{}
+	r.SetMoney(Money{1500, "JPY"})
+
+	assert.Equal(t, 1500, r.Amount)
+	assert.Equal(t, "JPY", r.Currency)
+	assert.Equal(t, Money{1500, "JPY"}, r.Money())
+
+	r.Currency = "ABC"
+	r.MerchantID = "test"
+	assert.EqualError(t, r.Validate(), "CURRENCY: "+currencyUnknown+".")
+}
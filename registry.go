@@ -0,0 +1,81 @@
+package hpp
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Registry maps merchant IDs and sub-accounts to the HPP used to sign their requests and
+// verify their responses, for integrations running several merchants.
+type Registry struct {
+	mu        sync.RWMutex
+	merchants map[registryKey]*HPP
+}
+
+type registryKey struct {
+	merchantID string
+	account    string
+}
+
+// NewRegistry builds an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{merchants: map[registryKey]*HPP{}}
+}
+
+// Register the HPP for a merchant ID and sub-account.
+// An empty account is used for any of the merchant's sub-accounts that are not registered.
+func (reg *Registry) Register(merchantID, account string, hpp HPP) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.merchants[registryKey{merchantID, account}] = &hpp
+}
+
+// Lookup finds the HPP for a merchant ID and sub-account, falling back to the merchant's default
+func (reg *Registry) Lookup(merchantID, account string) (*HPP, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	if hpp, ok := reg.merchants[registryKey{merchantID, account}]; ok {
+		return hpp, nil
+	}
+
+	if hpp, ok := reg.merchants[registryKey{merchantID, ""}]; ok {
+		return hpp, nil
+	}
+
+	return nil, errors.Errorf("no merchant registered for merchant ID %q account %q", merchantID, account)
+}
+
+// ToJSON produces JSON from a Request, signed with the secret of the request's merchant
+func (reg *Registry) ToJSON(req Request, encoded bool) (json.RawMessage, error) {
+	hpp, err := reg.Lookup(req.MerchantID, req.Account)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sign request")
+	}
+
+	return hpp.ToJSON(req, encoded)
+}
+
+// FromJSON produces a Response from a JSON response, verified with the secret of the response's merchant
+func (reg *Registry) FromJSON(data []byte, encoded bool) (*Response, error) {
+	var resp Response
+	var err error
+	if encoded {
+		err = UnmarshalJSONEncoded(&resp, data)
+	} else {
+		err = json.Unmarshal(data, &resp)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read merchant from json")
+	}
+
+	hpp, err := reg.Lookup(resp.MerchantID, resp.Account)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to verify response")
+	}
+
+	return hpp.FromJSON(data, encoded)
+}
@@ -0,0 +1,145 @@
+package hpp
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRegistry() *Registry {
+	reg := NewRegistry()
+	reg.Register("thestore", "", New("mysecret"))
+	reg.Register("thestore", "internet", New("internetsecret"))
+	reg.Register("otherstore", "", HPP{Secret: "othersecret", Algorithm: SHA256})
+
+	return reg
+}
+
+func TestRegistryLookup(t *testing.T) {
+	reg := testRegistry()
+
+	var tests = []struct {
+		//given
+		description string
+		merchantID  string
+		account     string
+
+		//expected
+		secret string
+		err    error
+	}{
+		{
+			"Given a registered merchant and account",
+			"thestore",
+			"internet",
+
+			"internetsecret",
+			nil,
+		},
+		{
+			"Given an unregistered account, the merchant default is used",
+			"thestore",
+			"myAccount",
+
+			"mysecret",
+			nil,
+		},
+		{
+			"Given an unregistered merchant",
+			"unknown",
+			"internet",
+
+			"",
+			fmt.Errorf(`no merchant registered for merchant ID "unknown" account "internet"`),
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		hpp, err := reg.Lookup(test.merchantID, test.account)
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+			assert.Equal(t, test.secret, hpp.Secret, test.description)
+		}
+	}
+}
+
+func TestRegistryToJSON(t *testing.T) {
+	reg := testRegistry()
+
+	req := testRequest(false, false, false)
+	req.MerchantID = "otherstore"
+
+	js, err := reg.ToJSON(req, false)
+	assert.Nil(t, err)
+
+	fields := map[string]string{}
+	assert.Nil(t, json.Unmarshal(js, &fields))
+	assert.Contains(t, fields, "SHA256HASH", "signs with the merchant's settings")
+
+	req.MerchantID = "unknown"
+	_, err = reg.ToJSON(req, false)
+	assert.EqualError(t, err, `unable to sign request: no merchant registered for merchant ID "unknown" account ""`)
+}
+
+func TestRegistryFromJSON(t *testing.T) {
+	reg := testRegistry()
+
+	var tests = []struct {
+		//given
+		description string
+		json        json.RawMessage
+		encoded     bool
+
+		//expected
+		err error
+	}{
+		{
+			"Given an encoded response for a registered merchant",
+			readSampleResponse("encoded-valid"),
+			true,
+
+			nil,
+		},
+		{
+			"Given a response for a registered merchant",
+			readSampleResponse("valid"),
+			false,
+
+			nil,
+		},
+		{
+			"Given a response for an unregistered merchant",
+			[]byte(`{"MERCHANT_ID": "unknown"}`),
+			false,
+
+			fmt.Errorf(`unable to verify response: no merchant registered for merchant ID "unknown" account ""`),
+		},
+		{
+			"Given invalid json",
+			[]byte(`invalid`),
+			false,
+
+			fmt.Errorf("unable to read merchant from json: invalid character 'i' looking for beginning of value"),
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		resp, err := reg.FromJSON(test.json, test.encoded)
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+			assert.Equal(t, "ORD453-11", resp.OrderID, test.description)
+		}
+	}
+}
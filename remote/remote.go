@@ -0,0 +1,352 @@
+// Package remote sends post-authorisation requests (settle, void, rebate, credit and release)
+// for HPP transactions to the Realex Remote API.
+package remote
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	hpp "github.com/Fatsoma/rxp-hpp-go"
+	"github.com/pkg/errors"
+)
+
+// Remote API endpoints
+const (
+	ProductionEndpoint = "https://epage.payandshop.com/epage-remote.cgi"
+	SandboxEndpoint    = "https://api.sandbox.realexpayments.com/epage-remote.cgi"
+)
+
+// RequestType is the type attribute of a Remote API request
+type RequestType string
+
+// Supported request types
+const (
+	Settle  RequestType = "settle"
+	Void    RequestType = "void"
+	Rebate  RequestType = "rebate"
+	Credit  RequestType = "credit"
+	Release RequestType = "release"
+)
+
+// Amount in the lowest unit of the currency
+type Amount struct {
+	Currency string `xml:"currency,attr"`
+	Value    int    `xml:",chardata"`
+}
+
+// Card details, only required for credit requests
+type Card struct {
+	Number  string `xml:"number"`
+	ExpDate string `xml:"expdate"`
+	ChName  string `xml:"chname"`
+	Type    string `xml:"type"`
+}
+
+// Request is a Remote API request
+type Request struct {
+	XMLName xml.Name `xml:"request"`
+
+	// The type of request.
+	Type RequestType `xml:"type,attr"`
+
+	// Date and time of the request in YYYYMMDDHHMMSS format.
+	TimeStamp string `xml:"timestamp,attr"`
+
+	// The merchant ID supplied by Realex Payments.
+	MerchantID string `xml:"merchantid"`
+
+	// The sub-account of the original transaction.
+	Account string `xml:"account,omitempty"`
+
+	// The order ID of the original transaction, or a new order ID for credits.
+	OrderID string `xml:"orderid"`
+
+	// The amount to settle, rebate or credit. Not sent for voids and releases.
+	Amount *Amount `xml:"amount,omitempty"`
+
+	// The card to credit.
+	Card *Card `xml:"card,omitempty"`
+
+	// The Realex Payments reference of the original transaction.
+	PasRef string `xml:"pasref,omitempty"`
+
+	// The authcode of the original transaction.
+	AuthCode string `xml:"authcode,omitempty"`
+
+	// The SHA-1 hash of the rebate password, required for rebates and credits.
+	RefundHash string `xml:"refundhash,omitempty"`
+
+	// Freeform comments to describe the request.
+	Comments *Comments `xml:"comments,omitempty"`
+
+	// A SHA-1 digital signature created using the request fields and your shared secret.
+	Hash string `xml:"sha1hash"`
+}
+
+// Comments on a request, up to two are accepted
+type Comments struct {
+	Comment []Comment `xml:"comment"`
+}
+
+// Comment is a freeform comment on a request
+type Comment struct {
+	ID    int    `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+// NewRequest builds a request of the given type for the transaction described by an HPP response
+func NewRequest(t RequestType, resp *hpp.Response) *Request {
+	return &Request{
+		Type:       t,
+		MerchantID: resp.MerchantID,
+		Account:    resp.Account,
+		OrderID:    resp.OrderID,
+		PasRef:     resp.PasRef,
+		AuthCode:   resp.AuthCode,
+	}
+}
+
+// BuildHash creates the security hash from
+// TIMESTAMP.MERCHANT_ID.ORDER_ID.AMOUNT.CURRENCY.CARD_NUMBER and the shared secret.
+func (r *Request) BuildHash(secret string) {
+	amount, currency, number := "", "", ""
+	if r.Amount != nil {
+		amount = strconv.Itoa(r.Amount.Value)
+		currency = r.Amount.Currency
+	}
+
+	if r.Card != nil {
+		number = r.Card.Number
+	}
+
+	s := []string{r.TimeStamp, r.MerchantID, r.OrderID, amount, currency, number}
+	r.Hash = hpp.GenerateHash(strings.Join(s, hpp.Separator), secret)
+}
+
+// Response is a Remote API response
+type Response struct {
+	XMLName xml.Name `xml:"response"`
+
+	// Date and time of the response in YYYYMMDDHHMMSS format.
+	TimeStamp string `xml:"timestamp,attr"`
+
+	// The merchant ID of the request.
+	MerchantID string `xml:"merchantid"`
+
+	// The sub-account of the request.
+	Account string `xml:"account"`
+
+	// The order ID of the request.
+	OrderID string `xml:"orderid"`
+
+	// The outcome of the request. Will contain "00" if the request was a success.
+	Result string `xml:"result"`
+
+	// Will contain a text message that describes the result code.
+	Message string `xml:"message"`
+
+	// A unique reference that Realex Payments assign to the request.
+	PasRef string `xml:"pasref"`
+
+	// The authcode of the transaction.
+	AuthCode string `xml:"authcode"`
+
+	// The Realex Payments batch the transaction will be in.
+	BatchID string `xml:"batchid"`
+
+	// A SHA-1 digital signature created using the response fields and your shared secret.
+	Hash string `xml:"sha1hash"`
+}
+
+// Success is true if the request was a success
+func (r *Response) Success() bool {
+	return r.ResultCode().IsSuccess()
+}
+
+// ResultCode is the typed result of the request
+func (r *Response) ResultCode() hpp.ResultCode {
+	return hpp.ResultCode(r.Result)
+}
+
+// BuildHash creates the security hash from
+// TIMESTAMP.MERCHANT_ID.ORDER_ID.RESULT.MESSAGE.PASREF.AUTHCODE and the shared secret.
+func (r *Response) BuildHash(secret string) string {
+	s := []string{r.TimeStamp, r.MerchantID, r.OrderID, r.Result, r.Message, r.PasRef, r.AuthCode}
+
+	return hpp.GenerateHash(strings.Join(s, hpp.Separator), secret)
+}
+
+// ValidateHash ensures the response hash is what we expect it to be
+func (r *Response) ValidateHash(secret string) error {
+	if subtle.ConstantTimeCompare([]byte(r.BuildHash(secret)), []byte(r.Hash)) != 1 {
+		return &hpp.HashMismatchError{Received: r.Hash}
+	}
+
+	return nil
+}
+
+// Client sends requests to the Remote API
+type Client struct {
+	// Endpoint the requests are posted to, defaults to ProductionEndpoint.
+	Endpoint string
+
+	// Secret shared with Realex Payments.
+	Secret string
+
+	// RebatePassword is required for rebates and credits.
+	RebatePassword string
+
+	// HTTPClient used to post requests, defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// OrderIDs generates the order ID of credits, defaults to hpp.UUIDOrderIDs.
+	OrderIDs hpp.OrderIDGenerator
+
+	now func() time.Time
+}
+
+// New builds a new Client for the production endpoint
+func New(secret string) *Client {
+	return &Client{Endpoint: ProductionEndpoint, Secret: secret}
+}
+
+// Settle the transaction described by an HPP response.
+// The amount can be up to 115% of the authorised amount. The ceiling is only checked when the
+// response has an amount, e.g. not for a response rebuilt from stored references.
+func (c *Client) Settle(resp *hpp.Response, amount int, currency string) (*Response, error) {
+	if resp.Amount > 0 {
+		ceiling := hpp.Money{Amount: resp.Amount, Currency: currency}.SettleCeiling()
+		if amount > ceiling.Amount {
+			return nil, errors.Errorf("cannot settle %d, more than the ceiling of %d", amount, ceiling.Amount)
+		}
+	}
+
+	req := NewRequest(Settle, resp)
+	req.Amount = &Amount{Currency: currency, Value: amount}
+
+	return c.Do(req)
+}
+
+// Void the transaction described by an HPP response
+func (c *Client) Void(resp *hpp.Response) (*Response, error) {
+	return c.Do(NewRequest(Void, resp))
+}
+
+// Rebate (refund) an amount of the transaction described by an HPP response
+func (c *Client) Rebate(resp *hpp.Response, amount int, currency string) (*Response, error) {
+	req := NewRequest(Rebate, resp)
+	req.Amount = &Amount{Currency: currency, Value: amount}
+
+	return c.Do(req)
+}
+
+// Credit an amount to a card, using the merchant ID and account of an HPP response.
+// A credit is a new transaction, so it gets a new order ID from OrderIDs and does not reference
+// the PASREF or authcode of the response. The card number is included in the request hash.
+func (c *Client) Credit(resp *hpp.Response, amount int, currency string, card Card) (*Response, error) {
+	req := &Request{
+		Type:       Credit,
+		MerchantID: resp.MerchantID,
+		Account:    resp.Account,
+		OrderID:    c.orderIDs().OrderID(c.clock()),
+		Amount:     &Amount{Currency: currency, Value: amount},
+		Card:       &card,
+	}
+
+	return c.Do(req)
+}
+
+// Release the transaction described by an HPP response, held by the fraud filter
+func (c *Client) Release(resp *hpp.Response) (*Response, error) {
+	return c.Do(NewRequest(Release, resp))
+}
+
+// Do signs and sends a request, and verifies the response hash
+func (c *Client) Do(req *Request) (*Response, error) {
+	if req.TimeStamp == "" {
+		req.TimeStamp = c.clock().UTC().Format(hpp.TimeLayout)
+	}
+
+	if req.Type == Rebate || req.Type == Credit {
+		if c.RebatePassword == "" {
+			return nil, errors.Errorf("rebate password is required for %s requests", req.Type)
+		}
+
+		req.RefundHash = fmt.Sprintf("%x", sha1.Sum([]byte(c.RebatePassword)))
+	}
+
+	req.BuildHash(c.Secret)
+
+	body, err := xml.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal remote request")
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = ProductionEndpoint
+	}
+
+	httpResp, err := c.httpClient().Post(endpoint, "text/xml", bytes.NewReader(append([]byte(xml.Header), body...)))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to send remote request")
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected remote response status %d", httpResp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read remote response")
+	}
+
+	resp := &Response{}
+	err = xml.Unmarshal(data, resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal remote response")
+	}
+
+	// error responses from the gateway are not always signed
+	if resp.Success() || resp.Hash != "" {
+		err = resp.ValidateHash(c.Secret)
+		if err != nil {
+			return nil, errors.Wrap(err, "secret does not match expected")
+		}
+	}
+
+	return resp, nil
+}
+
+func (c *Client) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+
+	return time.Now()
+}
+
+func (c *Client) orderIDs() hpp.OrderIDGenerator {
+	if c.OrderIDs != nil {
+		return c.OrderIDs
+	}
+
+	return hpp.UUIDOrderIDs{}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	return http.DefaultClient
+}
@@ -0,0 +1,253 @@
+package remote
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hpp "github.com/Fatsoma/rxp-hpp-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBuildHash(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		request     Request
+
+		//expected
+		hash string
+	}{
+		{
+			"Given a request with an amount, the amount and currency are hashed",
+			Request{
+				TimeStamp:  "20130814122239",
+				MerchantID: "thestore",
+				OrderID:    "ORD453-11",
+				Amount:     &Amount{Currency: "EUR", Value: 29900},
+			},
+
+			"35ab1f84b9ac619061041cd311b25386469d43bb",
+		},
+		{
+			"Given a request without an amount, empty fields are hashed",
+			Request{
+				TimeStamp:  "20130814122239",
+				MerchantID: "thestore",
+				OrderID:    "ORD453-11",
+			},
+
+			"4a54dbc8789130ece5b9a3c93137c98da682ddec",
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		r := test.request
+		r.BuildHash("mysecret")
+
+		// Assertions
+		assert.Equal(t, test.hash, r.Hash, test.description)
+	}
+}
+
+func TestClientDo(t *testing.T) {
+	original := &hpp.Response{
+		MerchantID: "thestore",
+		Account:    "internet",
+		OrderID:    "ORD453-11",
+		Amount:     29900,
+		PasRef:     "3737468273643",
+		AuthCode:   "79347",
+	}
+
+	var tests = []struct {
+		//given
+		description string
+		send        func(c *Client) (*Response, error)
+		result      string
+		tamper      bool
+
+		//expected
+		request string
+		err     error
+	}{
+		{
+			"Given a settle request",
+			func(c *Client) (*Response, error) { return c.Settle(original, 29900, "EUR") },
+			"00",
+			false,
+
+			`<request type="settle" timestamp="20130814122239"><merchantid>thestore</merchantid><account>internet</account>` +
+				`<orderid>ORD453-11</orderid><amount currency="EUR">29900</amount><pasref>3737468273643</pasref>` +
+				`<authcode>79347</authcode><sha1hash>35ab1f84b9ac619061041cd311b25386469d43bb</sha1hash></request>`,
+			nil,
+		},
+		{
+			"Given a rebate request, the rebate password hash is sent",
+			func(c *Client) (*Response, error) { return c.Rebate(original, 29900, "EUR") },
+			"00",
+			false,
+
+			`<request type="rebate" timestamp="20130814122239"><merchantid>thestore</merchantid><account>internet</account>` +
+				`<orderid>ORD453-11</orderid><amount currency="EUR">29900</amount><pasref>3737468273643</pasref>` +
+				`<authcode>79347</authcode><refundhash>fe528c20a04fb494f796591392ede2d36140b471</refundhash>` +
+				`<sha1hash>35ab1f84b9ac619061041cd311b25386469d43bb</sha1hash></request>`,
+			nil,
+		},
+		{
+			"Given a credit request, a new order ID and the card are sent and the card number hashed",
+			func(c *Client) (*Response, error) {
+				return c.Credit(original, 1000, "EUR", Card{
+					Number:  "4263971921001307",
+					ExpDate: "0425",
+					ChName:  "James Mason",
+					Type:    "VISA",
+				})
+			},
+			"00",
+			false,
+
+			`<request type="credit" timestamp="20130814122239"><merchantid>thestore</merchantid><account>internet</account>` +
+				`<orderid>ORD453-12</orderid><amount currency="EUR">1000</amount><card><number>4263971921001307</number>` +
+				`<expdate>0425</expdate><chname>James Mason</chname><type>VISA</type></card>` +
+				`<refundhash>fe528c20a04fb494f796591392ede2d36140b471</refundhash>` +
+				`<sha1hash>05e22aea0b977319e45909c11e1975dfa7e8fcad</sha1hash></request>`,
+			nil,
+		},
+		{
+			"Given a void request, no amount is sent",
+			func(c *Client) (*Response, error) { return c.Void(original) },
+			"00",
+			false,
+
+			`<request type="void" timestamp="20130814122239"><merchantid>thestore</merchantid><account>internet</account>` +
+				`<orderid>ORD453-11</orderid><pasref>3737468273643</pasref><authcode>79347</authcode>` +
+				`<sha1hash>4a54dbc8789130ece5b9a3c93137c98da682ddec</sha1hash></request>`,
+			nil,
+		},
+		{
+			"Given a release request",
+			func(c *Client) (*Response, error) { return c.Release(original) },
+			"00",
+			false,
+
+			`<request type="release" timestamp="20130814122239"><merchantid>thestore</merchantid><account>internet</account>` +
+				`<orderid>ORD453-11</orderid><pasref>3737468273643</pasref><authcode>79347</authcode>` +
+				`<sha1hash>4a54dbc8789130ece5b9a3c93137c98da682ddec</sha1hash></request>`,
+			nil,
+		},
+		{
+			"Given a settle request for more than 115% of the original amount",
+			func(c *Client) (*Response, error) { return c.Settle(original, 34386, "EUR") },
+			"00",
+			false,
+
+			"",
+			fmt.Errorf("cannot settle 34386, more than the ceiling of 34385"),
+		},
+		{
+			"Given a settle request for a response without an amount, the ceiling is not checked",
+			func(c *Client) (*Response, error) {
+				return c.Settle(&hpp.Response{MerchantID: "thestore", OrderID: "ORD453-11", PasRef: "3737468273643"}, 50000, "EUR")
+			},
+			"00",
+			false,
+
+			`<request type="settle" timestamp="20130814122239"><merchantid>thestore</merchantid>` +
+				`<orderid>ORD453-11</orderid><amount currency="EUR">50000</amount><pasref>3737468273643</pasref>` +
+				`<sha1hash>bdbffbdaace60feb8a0aa582413fef8f94a0e2b6</sha1hash></request>`,
+			nil,
+		},
+		{
+			"Given a tampered response",
+			func(c *Client) (*Response, error) { return c.Void(original) },
+			"00",
+			true,
+
+			"",
+			fmt.Errorf("secret does not match expected: hash mismatch, received TEST"),
+		},
+	}
+
+	for _, test := range tests {
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			received = string(body)
+
+			resp := Response{
+				TimeStamp:  "20130814122240",
+				MerchantID: "thestore",
+				OrderID:    "ORD453-11",
+				Result:     test.result,
+				Message:    "Successful",
+				PasRef:     "3737468273644",
+			}
+			resp.Hash = resp.BuildHash("mysecret")
+			if test.tamper {
+				resp.Hash = "TEST"
+			}
+
+			xml.NewEncoder(w).Encode(resp)
+		}))
+
+		c := &Client{
+			Endpoint:       server.URL,
+			Secret:         "mysecret",
+			RebatePassword: "rebate",
+			OrderIDs:       hpp.OrderIDFunc(func(time.Time) string { return "ORD453-12" }),
+			now:            func() time.Time { return time.Date(2013, 8, 14, 12, 22, 39, 0, time.UTC) },
+		}
+
+		// Subject
+		resp, err := test.send(c)
+		server.Close()
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+			assert.Equal(t, test.tamper, errors.Is(err, hpp.ErrHashMismatch), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+			assert.Equal(t, xml.Header+test.request, received, test.description)
+			assert.True(t, resp.Success(), test.description)
+			assert.Equal(t, "3737468273644", resp.PasRef, test.description)
+		}
+	}
+}
+
+func TestClientDoErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unsigned" {
+			w.Write([]byte(`<response timestamp="20130814122240"><result>508</result><message>Invalid request</message></response>`))
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := &Client{Endpoint: server.URL + "/unsigned", Secret: "mysecret"}
+	resp, err := c.Do(&Request{Type: Void})
+	if assert.Nil(t, err, "unsigned error responses are returned") {
+		assert.Equal(t, "508", resp.Result)
+		assert.False(t, resp.Success())
+		assert.True(t, resp.ResultCode().IsMerchantConfigError())
+	}
+
+	c.Endpoint = server.URL
+	_, err = c.Do(&Request{Type: Void})
+	assert.EqualError(t, err, "unexpected remote response status 500")
+
+	_, err = c.Do(&Request{Type: Rebate})
+	assert.EqualError(t, err, "rebate password is required for rebate requests")
+
+	_, err = c.Credit(&hpp.Response{}, 1000, "EUR", Card{})
+	assert.EqualError(t, err, "rebate password is required for credit requests")
+}
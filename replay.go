@@ -0,0 +1,215 @@
+package hpp
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrStaleResponse is matched by errors.Is when a response TIMESTAMP is outside the MaxResponseAge of the HPP
+var ErrStaleResponse = errors.New("stale response")
+
+// ErrReplayedResponse is matched by errors.Is when a response has already been accepted
+var ErrReplayedResponse = errors.New("replayed response")
+
+// StaleResponseError is returned when a response TIMESTAMP is missing or outside the MaxResponseAge of the HPP
+type StaleResponseError struct {
+	TimeStamp time.Time
+	Age       time.Duration
+}
+
+func (e *StaleResponseError) Error() string {
+	if e.TimeStamp.IsZero() {
+		return "stale response, no timestamp"
+	}
+
+	return fmt.Sprintf("stale response, timestamp %s is %s old", e.TimeStamp.Format(TimeLayout), e.Age)
+}
+
+// Is reports whether the target is ErrStaleResponse
+func (e *StaleResponseError) Is(target error) bool {
+	return target == ErrStaleResponse
+}
+
+// ReplayedResponseError is returned when the ORDER_ID and PASREF of a response have already been accepted
+type ReplayedResponseError struct {
+	OrderID string
+	PasRef  string
+}
+
+func (e *ReplayedResponseError) Error() string {
+	return fmt.Sprintf("replayed response, order ID %s pasref %s already accepted", e.OrderID, e.PasRef)
+}
+
+// Is reports whether the target is ErrReplayedResponse
+func (e *ReplayedResponseError) Is(target error) bool {
+	return target == ErrReplayedResponse
+}
+
+// SeenStore records the responses accepted by an HPP so a response can only be accepted once
+type SeenStore interface {
+	// Seen records the order ID and PASREF, returning true if they were already recorded
+	Seen(orderID, pasRef string) (bool, error)
+}
+
+type seenKey struct {
+	orderID string
+	pasRef  string
+}
+
+// MemorySeenStore is a SeenStore for a single process, its records are lost on restart
+type MemorySeenStore struct {
+	mu   sync.Mutex
+	seen map[seenKey]bool
+}
+
+// NewMemorySeenStore builds an empty MemorySeenStore
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: map[seenKey]bool{}}
+}
+
+// Seen records the order ID and PASREF, returning true if they were already recorded
+func (s *MemorySeenStore) Seen(orderID, pasRef string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := seenKey{orderID, pasRef}
+	if s.seen[k] {
+		return true, nil
+	}
+	s.seen[k] = true
+
+	return false, nil
+}
+
+// FileSeenStore is a SeenStore which appends its records to a file, one ORDER_ID,PASREF CSV record
+// per line, so they survive restarts. Records are also kept in memory, and malformed lines are
+// skipped when the file is opened.
+type FileSeenStore struct {
+	mu   sync.Mutex
+	file *os.File
+	seen map[seenKey]bool
+}
+
+// OpenFileSeenStore opens or creates the file and loads its records
+func OpenFileSeenStore(path string) (*FileSeenStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open seen store")
+	}
+
+	s := &FileSeenStore{file: f, seen: map[seenKey]bool{}}
+
+	// each line is parsed on its own so a malformed line, e.g. one half written before a
+	// crash, is skipped without losing the records after it
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		r := csv.NewReader(strings.NewReader(scanner.Text()))
+		r.FieldsPerRecord = -1
+
+		rec, err := r.Read()
+		if err == nil && len(rec) == 2 {
+			s.seen[seenKey{rec[0], rec[1]}] = true
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "unable to read seen store")
+	}
+
+	err = terminateLine(f)
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "unable to repair seen store")
+	}
+
+	return s, nil
+}
+
+// terminateLine ends a half written last line so the next record starts on its own line
+func terminateLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+
+	last := make([]byte, 1)
+	_, err = f.ReadAt(last, info.Size()-1)
+	if err != nil || last[0] == '\n' {
+		return err
+	}
+
+	_, err = f.Write([]byte("\n"))
+	return err
+}
+
+// Seen records the order ID and PASREF, returning true if they were already recorded
+func (s *FileSeenStore) Seen(orderID, pasRef string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := seenKey{orderID, pasRef}
+	if s.seen[k] {
+		return true, nil
+	}
+
+	w := csv.NewWriter(s.file)
+	w.Write([]string{orderID, pasRef})
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return false, errors.Wrap(err, "unable to write seen store")
+	}
+	s.seen[k] = true
+
+	return false, nil
+}
+
+// Close closes the file
+func (s *FileSeenStore) Close() error {
+	return s.file.Close()
+}
+
+// checkFresh ensures the response TIMESTAMP is within the MaxResponseAge of the HPP
+func (r *Response) checkFresh() error {
+	if r.hpp == nil || r.hpp.MaxResponseAge <= 0 {
+		return nil
+	}
+
+	if r.TimeStamp == nil {
+		return &StaleResponseError{}
+	}
+
+	ts := time.Time(*r.TimeStamp)
+	age := r.hpp.now().Sub(ts)
+	if age > r.hpp.MaxResponseAge || -age > r.hpp.MaxResponseAge {
+		return &StaleResponseError{TimeStamp: ts, Age: age}
+	}
+
+	return nil
+}
+
+// checkReplay records the response in the SeenStore of the HPP, failing if it was already recorded
+func (r *Response) checkReplay() error {
+	if r.hpp == nil || r.hpp.SeenStore == nil {
+		return nil
+	}
+
+	seen, err := r.hpp.SeenStore.Seen(r.OrderID, r.PasRef)
+	if err != nil {
+		return errors.Wrap(err, "unable to check for replayed response")
+	}
+
+	if seen {
+		return &ReplayedResponseError{OrderID: r.OrderID, PasRef: r.PasRef}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package hpp
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sampleResponseTime = time.Date(2013, 8, 14, 12, 22, 39, 0, time.UTC)
+
+func TestFromJSONMaxResponseAge(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		now         time.Time
+
+		//expected
+		err error
+	}{
+		{"Given a response within the window", sampleResponseTime.Add(4 * time.Minute), nil},
+		{"Given a response from the future within the window", sampleResponseTime.Add(-4 * time.Minute), nil},
+		{"Given a response older than the window", sampleResponseTime.Add(time.Hour), ErrStaleResponse},
+		{"Given a response further in the future than the window", sampleResponseTime.Add(-time.Hour), ErrStaleResponse},
+	}
+
+	for _, test := range tests {
+		hpp := HPP{Secret: "mysecret", Clock: FixedClock(test.now), MaxResponseAge: 5 * time.Minute}
+
+		// Subject
+		_, err := hpp.FromJSON(readSampleResponse("valid"), false)
+
+		// Assertions
+		if test.err == nil {
+			assert.Nil(t, err, test.description)
+		} else {
+			assert.True(t, errors.Is(err, test.err), test.description)
+		}
+	}
+}
+
+func TestStaleResponseError(t *testing.T) {
+	hpp := HPP{Secret: "mysecret", Clock: FixedClock(sampleResponseTime.Add(time.Hour)), MaxResponseAge: time.Minute}
+
+	_, err := hpp.FromJSON(readSampleResponse("valid"), false)
+
+	var stale *StaleResponseError
+	if assert.True(t, errors.As(err, &stale)) {
+		assert.Equal(t, sampleResponseTime, stale.TimeStamp)
+		assert.Equal(t, time.Hour, stale.Age)
+	}
+	assert.EqualError(t, err, "unable to build response from json: response rejected: stale response, timestamp 20130814122239 is 1h0m0s old")
+}
+
+func TestFromJSONSeenStore(t *testing.T) {
+	file, err := ioutil.TempFile("", "seen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	fileStore, err := OpenFileSeenStore(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fileStore.Close()
+
+	for _, store := range []SeenStore{NewMemorySeenStore(), fileStore} {
+		hpp := HPP{Secret: "mysecret", SeenStore: store}
+
+		_, err = hpp.FromJSON(readSampleResponse("valid"), false)
+		assert.Nil(t, err, "the first response is accepted")
+
+		_, err = hpp.FromJSON(readSampleResponse("valid"), false)
+		var replayed *ReplayedResponseError
+		if assert.True(t, errors.As(err, &replayed), "the replayed response is rejected") {
+			assert.Equal(t, &ReplayedResponseError{OrderID: "ORD453-11", PasRef: "3737468273643"}, replayed)
+		}
+		assert.True(t, errors.Is(err, ErrReplayedResponse))
+
+		tampered := strings.Replace(string(readSampleResponse("valid")), "ORD453-11", "ORD453-12", 1)
+		_, err = hpp.FromJSON([]byte(tampered), false)
+		assert.True(t, errors.Is(err, ErrHashMismatch))
+
+		seen, serr := store.Seen("ORD453-12", "3737468273643")
+		assert.Nil(t, serr)
+		assert.False(t, seen, "responses which fail verification are not recorded")
+	}
+}
+
+func TestOpenFileSeenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "seen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "seen.log")
+
+	store, err := OpenFileSeenStore(path)
+	if assert.Nil(t, err) {
+		seen, serr := store.Seen("ORD453-11", "3737468273643")
+		assert.Nil(t, serr)
+		assert.False(t, seen)
+
+		seen, serr = store.Seen("ORD453-13", "")
+		assert.Nil(t, serr)
+		assert.False(t, seen)
+		store.Close()
+	}
+
+	// Subject
+	store, err = OpenFileSeenStore(path)
+
+	// Assertions
+	if assert.Nil(t, err) {
+		defer store.Close()
+
+		seen, serr := store.Seen("ORD453-11", "3737468273643")
+		assert.Nil(t, serr)
+		assert.True(t, seen, "records are loaded from the file")
+
+		seen, serr = store.Seen("ORD453-13", "")
+		assert.Nil(t, serr)
+		assert.True(t, seen, "records with an empty pasref are loaded from the file")
+
+		seen, serr = store.Seen("ORD453-12", "3737468273643")
+		assert.Nil(t, serr)
+		assert.False(t, seen)
+	}
+
+	_, err = OpenFileSeenStore(filepath.Join(dir, "missing", "seen.log"))
+	assert.NotNil(t, err)
+}
+
+func TestOpenFileSeenStoreMalformed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "seen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "seen.log")
+
+	err = ioutil.WriteFile(path, []byte("ORD453-11,3737468273643\nORD453-12,\"37374\nORD453-13,3737468273645,X\nORD453-14"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Subject
+	store, err := OpenFileSeenStore(path)
+
+	// Assertions
+	if assert.Nil(t, err, "malformed and truncated lines are skipped") {
+		seen, serr := store.Seen("ORD453-11", "3737468273643")
+		assert.Nil(t, serr)
+		assert.True(t, seen, "records before the malformed lines are loaded")
+
+		seen, serr = store.Seen("ORD453-15", "3737468273646")
+		assert.Nil(t, serr)
+		assert.False(t, seen)
+		store.Close()
+	}
+
+	store, err = OpenFileSeenStore(path)
+	if assert.Nil(t, err) {
+		defer store.Close()
+
+		seen, serr := store.Seen("ORD453-15", "3737468273646")
+		assert.Nil(t, serr)
+		assert.True(t, seen, "records written after a truncated line are loaded")
+	}
+}
+
+func TestResponseHandlerReplay(t *testing.T) {
+	hpp := HPP{Secret: "mysecret", SeenStore: NewMemorySeenStore()}
+	handler := NewResponseHandler(&hpp, HandlerConfig{
+		OnTamper: func(r *http.Request, err error) Reply {
+			return Reply{Status: http.StatusForbidden, HTML: "Tampered"}
+		},
+	})
+
+	var statuses []int
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/hpp/response", strings.NewReader(testResponseForm(false).Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+		statuses = append(statuses, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusForbidden}, statuses)
+}
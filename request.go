@@ -3,14 +3,12 @@ package hpp
 import (
 	"encoding/base64"
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-ozzo/ozzo-validation"
 	"github.com/pkg/errors"
-	"github.com/satori/go.uuid"
 )
 
 // Request represents a request to be sent to HPP
@@ -28,6 +26,7 @@ type Request struct {
 
 	// Total amount to authorise in the lowest unit of the currency – i.e. 100 euro would be entered as 10000.
 	// If there is no decimal in the currency (e.g. JPY Yen) then contact Realex Payments. No decimal points are allowed.
+	// Use SetMoney with ParseMoney to convert decimal amounts for the currency.
 	// Amount should be set to 0 for OTB transactions (i.e. where validate card only is set to 1).
 	Amount int `json:"AMOUNT,string"`
 
@@ -38,6 +37,7 @@ type Request struct {
 	TimeStamp *time.Time `json:"TIMESTAMP"`
 
 	// A digital signature generated using the SHA-1 algorithm.
+	// Sent as SHA256HASH when the HPP uses the SHA256 algorithm.
 	Hash string `json:"SHA1HASH"`
 
 	// Used to signify whether or not you wish the transaction to be captured in the next batch.
@@ -60,13 +60,13 @@ type Request struct {
 	// The postcode or ZIP of the shipping address.
 	ShippingCode string `json:"SHIPPING_CODE,omitempty"`
 
-	// The country of the shipping address.
+	// The ISO 3166-1 alpha-2 country code of the shipping address (e.g. IE).
 	ShippingCountry string `json:"SHIPPING_CO,omitempty"`
 
 	// The postcode or ZIP of the billing address.
 	BillingCode string `json:"BILLING_CODE,omitempty"`
 
-	// The country of the billing address.
+	// The ISO 3166-1 alpha-2 country code of the billing address (e.g. IE).
 	BillingCountry string `json:"BILLING_CO,omitempty"`
 
 	// The customer number of the customer. You can send in any additional information about the transaction in this field,
@@ -118,6 +118,64 @@ type Request struct {
 	// The payer reference. If this flag is received, HPP will retrieve a list of the payment methods saved for that payer.
 	SelectStoredCard string `json:"HPP_SELECT_STORED_CARD,omitempty"`
 
+	// The customer's email address. Required for 3D Secure 2.
+	CustomerEmail string `json:"HPP_CUSTOMER_EMAIL,omitempty"`
+
+	// The customer's mobile phone number in the format <country code>|<number> (e.g. 44|07123456789).
+	CustomerMobilePhone string `json:"HPP_CUSTOMER_PHONENUMBER_MOBILE,omitempty"`
+
+	// The first line of the billing address.
+	BillingStreetOne string `json:"HPP_BILLING_STREET1,omitempty"`
+
+	// The second line of the billing address.
+	BillingStreetTwo string `json:"HPP_BILLING_STREET2,omitempty"`
+
+	// The third line of the billing address.
+	BillingStreetThree string `json:"HPP_BILLING_STREET3,omitempty"`
+
+	// The city of the billing address.
+	BillingCity string `json:"HPP_BILLING_CITY,omitempty"`
+
+	// The postcode or ZIP of the billing address.
+	BillingPostalCode string `json:"HPP_BILLING_POSTALCODE,omitempty"`
+
+	// The ISO 3166-1 numeric country code of the billing address (e.g. 826 for the United Kingdom).
+	BillingCountryCode string `json:"HPP_BILLING_COUNTRY,omitempty"`
+
+	// The first line of the shipping address.
+	ShippingStreetOne string `json:"HPP_SHIPPING_STREET1,omitempty"`
+
+	// The second line of the shipping address.
+	ShippingStreetTwo string `json:"HPP_SHIPPING_STREET2,omitempty"`
+
+	// The third line of the shipping address.
+	ShippingStreetThree string `json:"HPP_SHIPPING_STREET3,omitempty"`
+
+	// The city of the shipping address.
+	ShippingCity string `json:"HPP_SHIPPING_CITY,omitempty"`
+
+	// The postcode or ZIP of the shipping address.
+	ShippingPostalCode string `json:"HPP_SHIPPING_POSTALCODE,omitempty"`
+
+	// The ISO 3166-1 numeric country code of the shipping address (e.g. 826 for the United Kingdom).
+	ShippingCountryCode string `json:"HPP_SHIPPING_COUNTRY,omitempty"`
+
+	// Whether the shipping address matches the billing address. Can be "TRUE" or "FALSE".
+	AddressMatchIndicator string `json:"HPP_ADDRESS_MATCH_INDICATOR,omitempty"`
+
+	// Whether a 3D Secure challenge is requested. Can be "NO_PREFERENCE", "NO_CHALLENGE_REQUESTED",
+	// "CHALLENGE_PREFERRED" or "CHALLENGE_MANDATED".
+	ChallengeRequestIndicator string `json:"HPP_CHALLENGE_REQUEST_INDICATOR,omitempty"`
+
+	// The payment methods offered on HPP, separated by | (e.g. cards|paypal|sofort|ideal).
+	PaymentMethods string `json:"PM_METHODS,omitempty"`
+
+	// The URL the final status of alternative payment methods which are pending is sent to.
+	TransactionStatusURL string `json:"HPP_TX_STATUS_URL,omitempty"`
+
+	// The URL the response is posted to, overriding the merchant configuration.
+	MerchantResponseURL string `json:"MERCHANT_RESPONSE_URL,omitempty"`
+
 	// Anything else you sent to us in the request.
 	SupplementaryData map[string]interface{} `json:"-"`
 }
@@ -144,6 +202,11 @@ func (r *Request) MarshalJSON() ([]byte, error) {
 		return nil, errors.Wrap(err, "unable to marshal request supplementary data")
 	}
 
+	if key := r.hpp.hashAlgorithm().Key(); key != SHA1.Key() {
+		delete(sup, SHA1.Key())
+		sup[key] = r.Hash
+	}
+
 	return json.Marshal(sup)
 }
 
@@ -169,48 +232,67 @@ func (r *Request) timeStampJSON() *JSONTime {
 // Validates inputs, generates security hash, order ID and time stamp (if required)
 // Base64 encodes inputs, and serialises itself to JSON
 func (r *Request) ToJSON(encoded bool) (json.RawMessage, error) {
-	fmt.Println("Converting HppRequest to JSON.")
+	log := r.hpp.logger()
+	log.Debug("Converting HppRequest to JSON.", r.logFields(StageConvert))
 
-	fmt.Println("Generating defaults.")
+	log.Debug("Generating defaults.", r.logFields(StageDefaults))
 	r.GenerateDefaults()
 
+	if r.hpp.sanitise() {
+		log.Debug("Sanitising request.", r.logFields(StageSanitise))
+		for _, f := range r.Sanitise() {
+			fields := r.logFields(StageSanitise)
+			fields["field"] = f.Key
+			fields["truncated"] = f.Truncated
+			log.Info("Request field sanitised.", fields)
+		}
+	}
+
+	log.Debug("Building hash.", r.logFields(StageHash))
 	r.BuildHash(r.hpp.Secret)
 
-	fmt.Println("Validating request.")
+	log.Debug("Validating request.", r.logFields(StageValidate))
 	err := r.Validate()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to validate HPP request")
+		log.Error("Request failed validation.", withError(r.logFields(StageValidate), err))
+		return nil, errors.Wrap(localise(err, r.hpp.messageLanguage(r.Language)), "failed to validate HPP request")
 	}
 
 	if encoded {
+		log.Debug("Encoding request.", r.logFields(StageEncode))
 		return MarshalJSONEncoded(r, encoded)
 	}
 
 	return json.Marshal(r)
 }
 
-// GenerateDefaults sets the timestamp and order ID if they aren't already set
+func (r *Request) logFields(stage string) Fields {
+	return logFields(r.MerchantID, r.OrderID, stage)
+}
+
+// GenerateDefaults sets the timestamp and order ID if they aren't already set,
+// using the Clock and OrderIDs of the HPP
 func (r *Request) GenerateDefaults() {
 	if r.TimeStamp == nil {
 		// the documentation isn't clear on this but let's assume UTC
-		now := time.Now().UTC()
+		now := r.hpp.now().UTC()
 		r.TimeStamp = &now
 	}
 
 	if r.OrderID == "" {
-		r.OrderID = uuid.NewV4().String()
+		r.OrderID = r.hpp.orderIDs().OrderID(*r.TimeStamp)
 	}
 }
 
-// Validate the HPP request fields
+// Validate the HPP request fields, failing fields are reported in a *ValidationError
 func (r *Request) Validate() error {
-	return validation.ValidateStruct(r,
+	err := validation.ValidateStruct(r,
 		validateMerchantID(&r.MerchantID),
 		validateAccount(&r.Account),
 		validateOrderID(&r.OrderID),
-		validateAmount(&r.Amount),
+		validateAmount(&r.Amount, r.isOTB()),
 		validateCurrency(&r.Currency),
-		validateHash(&r.Hash),
+		validateHash(&r.Hash, r.hpp.hashAlgorithm()),
 		validateAutoSettleFlag(&r.AutoSettleFlag),
 		validateEnableCardStorage(&r.EnableCardStorage),
 		validateOfferSaveCard(&r.OfferSaveCard),
@@ -231,13 +313,34 @@ func (r *Request) Validate() error {
 		validatePayerReference(&r.PayerReference),
 		validatePaymentReference(&r.PaymentReference),
 		validatePayerExists(&r.PayerExists),
+		validateCustomerEmail(&r.CustomerEmail),
+		validateCustomerMobilePhone(&r.CustomerMobilePhone),
+		validateStreet(&r.BillingStreetOne),
+		validateStreet(&r.BillingStreetTwo),
+		validateStreet(&r.BillingStreetThree),
+		validateCity(&r.BillingCity),
+		validatePostalCode(&r.BillingPostalCode),
+		validateCountryCode(&r.BillingCountryCode),
+		validateStreet(&r.ShippingStreetOne),
+		validateStreet(&r.ShippingStreetTwo),
+		validateStreet(&r.ShippingStreetThree),
+		validateCity(&r.ShippingCity),
+		validatePostalCode(&r.ShippingPostalCode),
+		validateCountryCode(&r.ShippingCountryCode),
+		validateAddressMatchIndicator(&r.AddressMatchIndicator),
+		validateChallengeRequestIndicator(&r.ChallengeRequestIndicator),
+		validatePaymentMethods(&r.PaymentMethods),
+		validateTransactionStatusURL(&r.TransactionStatusURL),
+		validateMerchantResponseURL(&r.MerchantResponseURL),
 	)
+
+	return newValidationError(err)
 }
 
 // BuildHash creates the security hash from a number of fields and the shared secret.
 func (r *Request) BuildHash(secret string) {
 	s := r.buildHashString()
-	r.Hash = GenerateHash(s, secret)
+	r.Hash = r.hpp.hashAlgorithm().GenerateHash(s, secret)
 }
 
 func (r *Request) buildHashString() string {
@@ -261,6 +364,11 @@ func (r *Request) basicHash() []string {
 	return []string{r.timeStampStr(), r.MerchantID, orderID, amount, r.Currency}
 }
 
+// isOTB is true for open to buy transactions, which validate the card without an amount
+func (r *Request) isOTB() bool {
+	return r.ValidCardOnly == "1"
+}
+
 func (r *Request) canStoreCard() bool {
 	return r.EnableCardStorage == "1" || r.SelectStoredCard != ""
 }
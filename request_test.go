@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"log"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -32,7 +33,7 @@ func TestRequestMarshalJSON(t *testing.T) {
 				Account:           "myAccount",
 				Amount:            100,
 				AutoSettleFlag:    "1",
-				BillingCountry:    "IRELAND",
+				BillingCountry:    "IE",
 				BillingCode:       "123|56",
 				CardPaymentButton: "Submit Payment",
 				EnableCardStorage: "0",
@@ -47,7 +48,7 @@ func TestRequestMarshalJSON(t *testing.T) {
 				OfferSaveCard:     "0",
 				OrderID:           "OrderID",
 				Hash:              "5d8f05abd618e50db4861a61cc940112786474cf",
-				ShippingCountry:   "IRELAND",
+				ShippingCountry:   "IE",
 				ShippingCode:      "56|987",
 				TimeStamp:         &timestamp,
 				ProductID:         "ProductID",
@@ -183,6 +184,128 @@ func TestValidate(t *testing.T) {
 				validateCardOnlyPattern,
 			),
 		},
+		{
+			"Given 3D Secure 2 attributes that are too long",
+			Request{
+				Amount:              1,
+				MerchantID:          "test",
+				CustomerEmail:       randomString(250) + "@a.ie",
+				CustomerMobilePhone: "44|" + strings.Repeat("1", 17),
+				BillingStreetOne:    randomString(51),
+				BillingCity:         randomString(41),
+				BillingPostalCode:   randomString(17),
+				ShippingStreetThree: randomString(51),
+			},
+
+			fmt.Errorf(
+				"HPP_BILLING_CITY: %s; HPP_BILLING_POSTALCODE: %s; HPP_BILLING_STREET1: %s; "+
+					"HPP_CUSTOMER_EMAIL: %s; HPP_CUSTOMER_PHONENUMBER_MOBILE: %s; HPP_SHIPPING_STREET3: %s",
+				citySize,
+				postalCodeSize,
+				streetSize,
+				customerEmailSize,
+				customerMobilePhoneSize,
+				streetSize,
+			),
+		},
+		{
+			"Given 3D Secure 2 attributes that do not match their patterns",
+			Request{
+				Amount:                    1,
+				MerchantID:                "test",
+				CustomerEmail:             "not an email",
+				CustomerMobilePhone:       "07123456789",
+				BillingCountryCode:        "IE",
+				ShippingPostalCode:        "D02/X285",
+				ShippingCity:              "Dublin \u2603",
+				AddressMatchIndicator:     "YES",
+				ChallengeRequestIndicator: "ALWAYS",
+			},
+
+			fmt.Errorf(
+				"HPP_ADDRESS_MATCH_INDICATOR: %s; HPP_BILLING_COUNTRY: %s; HPP_CHALLENGE_REQUEST_INDICATOR: %s; "+
+					"HPP_CUSTOMER_EMAIL: %s; HPP_CUSTOMER_PHONENUMBER_MOBILE: %s; HPP_SHIPPING_CITY: %s; HPP_SHIPPING_POSTALCODE: %s",
+				addressMatchIndicatorPattern,
+				countryCodePattern,
+				challengeRequestIndicatorPattern,
+				customerEmailPattern,
+				customerMobilePhonePattern,
+				cityPattern,
+				postalCodePattern,
+			),
+		},
+		{
+			"Given an OTB request with an amount",
+			Request{MerchantID: "test", ValidCardOnly: "1", Amount: 100},
+
+			fmt.Errorf("AMOUNT: %s", amountOTB),
+		},
+		{
+			"Given an OTB request without an amount",
+			Request{MerchantID: "test", ValidCardOnly: "1"},
+
+			nil,
+		},
+		{
+			"Given a request which is not OTB without an amount",
+			Request{MerchantID: "test", ValidCardOnly: "0"},
+
+			fmt.Errorf("AMOUNT: is required"),
+		},
+		{
+			"Given unknown country codes",
+			Request{
+				Amount:              1,
+				MerchantID:          "test",
+				BillingCountry:      "IRELAND",
+				ShippingCountry:     "XX",
+				BillingCountryCode:  "999",
+				ShippingCountryCode: "000",
+			},
+
+			fmt.Errorf(
+				"BILLING_CO: %s; HPP_BILLING_COUNTRY: %s; HPP_SHIPPING_COUNTRY: %s; SHIPPING_CO: %s",
+				billingCountryUnknown,
+				countryCodeUnknown,
+				countryCodeUnknown,
+				shippingCountryUnknown,
+			),
+		},
+		{
+			"Given lowercase country codes, they are known",
+			Request{
+				Amount:          1,
+				MerchantID:      "test",
+				BillingCountry:  "ie",
+				ShippingCountry: "gb",
+			},
+
+			nil,
+		},
+		{
+			"Given valid 3D Secure 2 attributes",
+			Request{
+				Amount:                    1,
+				MerchantID:                "test",
+				PayerExists:               "0",
+				CustomerEmail:             "james.mason@example.com",
+				CustomerMobilePhone:       "44|07123456789",
+				BillingStreetOne:          "Flat 123",
+				BillingStreetTwo:          "House 456",
+				BillingStreetThree:        "Unit 4",
+				BillingCity:               "Halifax",
+				BillingPostalCode:         "W5 9HR",
+				BillingCountryCode:        "826",
+				ShippingStreetOne:         "Apartment 852",
+				ShippingCity:              "Chicago",
+				ShippingPostalCode:        "50001",
+				ShippingCountryCode:       "840",
+				AddressMatchIndicator:     "FALSE",
+				ChallengeRequestIndicator: "NO_PREFERENCE",
+			},
+
+			nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -243,6 +366,12 @@ func TestRequestBuildHash(t *testing.T) {
 
 			"39f637a321da4ebc3a433ed327b2c2921ad58fdb",
 		},
+		{
+			"Given the SHA256 algorithm, a SHA-256 hash is returned",
+			testRequestWithHPP(testRequest(false, false, false), &HPP{Secret: "mysecret", Algorithm: SHA256}),
+
+			"1a52904820880cdde83d912641ab010259e480cb6db38ed818bfc856207d90d0",
+		},
 	}
 
 	for _, test := range tests {
@@ -345,6 +474,12 @@ func testRequest(cardStorage, selectStoredCard, fraudFilterMode bool) Request {
 	return r
 }
 
+func testRequestWithHPP(r Request, hpp *HPP) Request {
+	r.hpp = hpp
+
+	return r
+}
+
 func randomString(n int) string {
 	var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-")
 
@@ -1,6 +1,7 @@
 package hpp
 
 import (
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -33,6 +34,7 @@ type Response struct {
 	TimeStamp *JSONTime `json:"TIMESTAMP"`
 
 	// A SHA-1 digital signature created using the HPP response fields and your shared secret.
+	// Received as SHA256HASH when the HPP uses the SHA256 algorithm.
 	Hash string `json:"SHA1HASH"`
 
 	// The outcome of the transaction. Will contain "00" if the transaction was a success or another value (depending on the error) if not.
@@ -48,7 +50,13 @@ type Response struct {
 	// I: CVV Not checked due to circumstances.
 	// U: CVV Not checked - issuer not certified.
 	// P: CVV Not Processed.
-	CvnResult string `json:"CVNRESULT"`
+	CvnResult CVNResult `json:"CVNRESULT"`
+
+	// The result of the Address Verification check of the postcode (if enabled), see CvnResult for the values.
+	AVSPostcodeResult AVSResult `json:"AVSPOSTCODERESULT"`
+
+	// The result of the Address Verification check of the address (if enabled), see CvnResult for the values.
+	AVSAddressResult AVSResult `json:"AVSADDRESSRESULT"`
 
 	// A unique reference that Realex Payments assign to your transaction.
 	PasRef string `json:"PASREF"`
@@ -77,56 +85,194 @@ type Response struct {
 	// Using the RealControl application you can request that Realex Payments return certain individual scores to you.
 	// These are identified by numbers - thus TSS_1032 would be the result of the check with id 1032.
 	// You can then use these specific checks in conjunction with RealScore score to ascertain whether or not you wish to continue with the settlement.
+	// The overall RealScore is kept in RESULT, use RealScore for the parsed scores.
 	TSS map[string]string `json:"TSS"`
 
+	// Whether the customer chose to use a stored card ("1") or entered new card details ("0").
+	// Only returned when card storage is enabled or a stored card could be selected.
+	RealWalletChosen string `json:"REALWALLET_CHOSEN"`
+
+	// The outcome of setting up the payer. "00" if the payer was set up successfully.
+	PayerSetup string `json:"PAYER_SETUP"`
+
+	// Will contain a text message that describes the payer setup result.
+	PayerSetupMessage string `json:"PAYER_SETUP_MSG"`
+
+	// The outcome of storing the card. "00" if the card was stored successfully.
+	PaymentSetup string `json:"PMT_SETUP"`
+
+	// Will contain a text message that describes the card storage result.
+	PaymentSetupMessage string `json:"PMT_SETUP_MSG"`
+
+	// The payer reference the card is stored against.
+	SavedPayerReference string `json:"SAVED_PAYER_REF"`
+
+	// The type of the stored card (e.g. VISA, MC).
+	SavedPaymentType string `json:"SAVED_PMT_TYPE"`
+
+	// The payment reference of the stored card.
+	SavedPaymentReference string `json:"SAVED_PMT_REF"`
+
+	// The masked card number of the stored card.
+	SavedPaymentDigits string `json:"SAVED_PMT_DIGITS"`
+
+	// The expiry date of the stored card in MMYY format.
+	SavedPaymentExpiryDate string `json:"SAVED_PMT_EXPDATE"`
+
+	// The cardholder name of the stored card.
+	SavedPaymentName string `json:"SAVED_PMT_NAME"`
+
+	// The outcome of the fraud filter, only returned when the merchant is configured for fraud.
+	// Included in the response hash when present.
+	FraudFilterResult FraudResult `json:"HPP_FRAUDFILTER_RESULT"`
+
+	// The outcome of each fraud filter rule, ordered by rule ID.
+	FraudFilterRules []FraudRule `json:"-"`
+
+	// The alternative payment method used (e.g. paypal), empty for card payments.
+	PaymentMethod PaymentMethod `json:"PAYMENTMETHOD"`
+
+	// The reference shown to the customer by the alternative payment method.
+	PaymentPurpose string `json:"PAYMENT_PURPOSE"`
+
 	// Anything else you sent to us in the request will be returned to you in supplementary data.
 	SupplementaryData map[string]interface{} `json:"-"`
+
+	// The secret which verified the response hash. 0 is the primary secret,
+	// n is the nth secondary secret of the HPP.
+	VerifiedBy int `json:"-"`
 }
 
 // FromJSON converts valid JSON into the Response
 func (r *Response) FromJSON(data []byte, encoded bool) error {
-	fmt.Println("Converting JSON to HppResponse.")
+	log := r.hpp.logger()
+	log.Debug("Converting JSON to HppResponse.", r.logFields(StageConvert))
 
 	if encoded {
 		err := UnmarshalJSONEncoded(r, data)
 		if err != nil {
+			log.Error("Unable to decode response.", withError(r.logFields(StageDecode), err))
 			return errors.Wrap(err, "unable to unmarshal encoded response from json")
 		}
 	} else {
 		err := json.Unmarshal(data, r)
 		if err != nil {
+			log.Error("Unable to decode response.", withError(r.logFields(StageDecode), err))
 			return errors.Wrap(err, "unable to unmarshal response from json")
 		}
 	}
 
-	fmt.Println("Validating response hash.")
-	err := r.ValidateHash(r.hpp.Secret)
+	log.Debug("Validating response hash.", r.logFields(StageVerify))
+	verifiedBy, err := r.ValidateHashes(r.hpp.secrets()...)
 	if err != nil {
+		log.Warn("Response hash does not match.", withError(r.logFields(StageVerify), err))
 		return errors.Wrap(err, "secret does not match expected")
 	}
+	r.VerifiedBy = verifiedBy
+
+	err = r.checkFresh()
+	if err == nil {
+		err = r.checkReplay()
+	}
+	if err != nil {
+		log.Warn("Response rejected.", withError(r.logFields(StageVerify), err))
+		return errors.Wrap(err, "response rejected")
+	}
+
+	fields := r.logFields(StageVerify)
+	fields["verified_by"] = verifiedBy
+	log.Info("Response verified.", fields)
 
 	return nil
 }
 
+func (r *Response) logFields(stage string) Fields {
+	return logFields(r.MerchantID, r.OrderID, stage)
+}
+
+// PayerSaved is true if the payer was set up in card storage
+func (r *Response) PayerSaved() bool {
+	return r.PayerSetup == "00"
+}
+
+// CardSaved is true if the card was stored against the payer
+func (r *Response) CardSaved() bool {
+	return r.PaymentSetup == "00"
+}
+
+// UsedStoredCard is true if the customer paid with a stored card
+func (r *Response) UsedStoredCard() bool {
+	return r.RealWalletChosen == "1"
+}
+
+// ErrHashMismatch is matched by errors.Is when a response hash does not match the expected hash
+var ErrHashMismatch = errors.New("hash mismatch")
+
+// HashMismatchError is returned when a response hash does not match the expected hash,
+// which means the response has been tampered with or signed with another secret.
+// Only the received hash is reported so the expected signature never ends up in logs.
+type HashMismatchError struct {
+	Received string
+}
+
+func (e *HashMismatchError) Error() string {
+	return fmt.Sprintf("hash mismatch, received %s", e.Received)
+}
+
+// Is reports whether the target is ErrHashMismatch
+func (e *HashMismatchError) Is(target error) bool {
+	return target == ErrHashMismatch
+}
+
 // ValidateHash ensure the HPP response hash is what we expect it to be
 func (r *Response) ValidateHash(secret string) error {
 	expected := r.BuildHash(secret)
-	if expected != r.Hash {
-		return fmt.Errorf("expected hash %s received %s", expected, r.Hash)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(r.Hash)) != 1 {
+		return &HashMismatchError{Received: r.Hash}
 	}
 
 	return nil
 }
 
+// ValidateHashes ensures the HPP response hash matches one of the secrets and returns the index
+// of the secret that matched. If none match the error for the first secret is returned.
+func (r *Response) ValidateHashes(secrets ...string) (int, error) {
+	var first error
+	for i, secret := range secrets {
+		err := r.ValidateHash(secret)
+		if err == nil {
+			return i, nil
+		}
+
+		if first == nil {
+			first = err
+		}
+	}
+
+	if first == nil {
+		first = errors.New("no secrets to validate hash against")
+	}
+
+	return 0, first
+}
+
 // UnmarshalJSON override the standard JSON unmarshaller to include the supplementary data
 func (r *Response) UnmarshalJSON(data []byte) error {
 	type Alias Response
-	ra := (*Alias)(r)
-	err := json.Unmarshal(data, &ra)
+	ra := &struct {
+		TSS json.RawMessage `json:"TSS"`
+		*Alias
+	}{Alias: (*Alias)(r)}
+	err := json.Unmarshal(data, ra)
 	if err != nil {
 		return errors.Wrap(err, "unable to unmarshal response")
 	}
 
+	r.TSS, err = unmarshalTSS(ra.TSS)
+	if err != nil {
+		return errors.Wrap(err, "unable to unmarshal response TSS")
+	}
+
 	// Add the supplementary data from the response
 	extra := map[string]interface{}{}
 	err = json.Unmarshal(data, &extra)
@@ -139,6 +285,14 @@ func (r *Response) UnmarshalJSON(data []byte) error {
 		delete(extra, k)
 	}
 
+	if key := r.hpp.hashAlgorithm().Key(); key != SHA1.Key() {
+		if h, ok := extra[key].(string); ok {
+			r.Hash = h
+		}
+		delete(extra, key)
+	}
+
+	r.FraudFilterRules = takeFraudRules(extra)
 	r.SupplementaryData = extra
 
 	return nil
@@ -153,7 +307,11 @@ func (r *Response) BuildHash(secret string) string {
 
 	s := []string{ts, r.MerchantID, r.OrderID, r.Result, r.Message, r.PasRef, r.AuthCode}
 
-	return GenerateHash(strings.Join(s, Separator), secret)
+	if r.FraudFilterResult != "" {
+		s = append(s, string(r.FraudFilterResult))
+	}
+
+	return r.hpp.hashAlgorithm().GenerateHash(strings.Join(s, Separator), secret)
 }
 
 func (r Response) jsonfields() (names []string) {
@@ -84,6 +84,26 @@ func TestResponseUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestResponseCardStorage(t *testing.T) {
+	hpp := New("mysecret")
+
+	resp, err := hpp.FromJSON(readSampleResponse("card-storage"), false)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "5e7e9152-2d53-466d-91bc-6d12ebc56b79", resp.SavedPayerReference)
+		assert.Equal(t, "MC", resp.SavedPaymentType)
+		assert.Equal(t, "ca46344d-4292-47dc-9ced-e8a42ce66977", resp.SavedPaymentReference)
+		assert.Equal(t, "542523xxxx4415", resp.SavedPaymentDigits)
+		assert.Equal(t, "1025", resp.SavedPaymentExpiryDate)
+		assert.Equal(t, "James Mason", resp.SavedPaymentName)
+		assert.Equal(t, "Successful", resp.PayerSetupMessage)
+		assert.Equal(t, "Successful", resp.PaymentSetupMessage)
+		assert.True(t, resp.PayerSaved())
+		assert.True(t, resp.CardSaved())
+		assert.False(t, resp.UsedStoredCard())
+		assert.Empty(t, resp.SupplementaryData, "stored card fields are not supplementary data")
+	}
+}
+
 func TestResponseBuildHash(t *testing.T) {
 	var tests = []struct {
 		//given
@@ -116,6 +136,63 @@ func TestResponseBuildHash(t *testing.T) {
 	}
 }
 
+func TestResponseValidateHashes(t *testing.T) {
+	resp := testResponse()
+	resp.Hash = "43f6065bede40f3e0d7d732352b832c0136189e4"
+
+	var tests = []struct {
+		//given
+		description string
+		secrets     []string
+
+		//expected
+		index int
+		err   error
+	}{
+		{
+			"Given the primary secret matches",
+			[]string{"mysecret", "oldsecret"},
+
+			0,
+			nil,
+		},
+		{
+			"Given a secondary secret matches",
+			[]string{"newsecret", "othersecret", "mysecret"},
+
+			2,
+			nil,
+		},
+		{
+			"Given no secret matches, the error for the first secret is returned",
+			[]string{"newsecret", "othersecret"},
+
+			0,
+			fmt.Errorf("hash mismatch, received %s", resp.Hash),
+		},
+		{
+			"Given no secrets",
+			nil,
+
+			0,
+			fmt.Errorf("no secrets to validate hash against"),
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		index, err := resp.ValidateHashes(test.secrets...)
+
+		// Assertions
+		if err != nil && assert.NotNil(t, test.err, test.description) {
+			assert.EqualError(t, err, test.err.Error(), test.description)
+		} else {
+			assert.Nil(t, test.err, test.description)
+		}
+		assert.Equal(t, test.index, index, test.description)
+	}
+}
+
 func TestUnmarshalJSONEncoded(t *testing.T) {
 	hpp := New("mysecret")
 
@@ -0,0 +1,155 @@
+package hpp
+
+// ResultCode is the RESULT of a transaction, "00" for success. Codes are grouped by their first
+// digit: 1xx declined or referred by the bank, 2xx bank errors, 3xx Realex Payments errors and
+// 5xx validation errors, with 666 for deactivated clients.
+type ResultCode string
+
+// Result codes of successful and pending transactions
+const (
+	ResultSuccess ResultCode = "00"
+
+	// ResultPending is returned for alternative payment methods whose outcome is confirmed later
+	ResultPending ResultCode = "01"
+)
+
+// ResultClass is the group a result code belongs to
+type ResultClass int
+
+// Result classes
+const (
+	ResultClassUnknown ResultClass = iota
+	ResultClassSuccess
+	ResultClassDeclined
+	ResultClassReferral
+	ResultClassBankError
+	ResultClassGatewayError
+	ResultClassValidationError
+	ResultClassClientDeactivated
+	ResultClassPending
+)
+
+var resultClassNames = map[ResultClass]string{
+	ResultClassUnknown:           "unknown",
+	ResultClassSuccess:           "success",
+	ResultClassDeclined:          "declined",
+	ResultClassReferral:          "referral",
+	ResultClassBankError:         "bank error",
+	ResultClassGatewayError:      "gateway error",
+	ResultClassValidationError:   "validation error",
+	ResultClassClientDeactivated: "client deactivated",
+	ResultClassPending:           "pending",
+}
+
+func (c ResultClass) String() string {
+	return resultClassNames[c]
+}
+
+// resultMessages describe the known result codes
+var resultMessages = map[ResultCode]string{
+	"00":  "Successful",
+	"01":  "Transaction pending",
+	"101": "Declined by bank",
+	"102": "Referral B, declined by bank",
+	"103": "Referral A, card reported lost or stolen",
+	"107": "Declined by fraud checks",
+	"111": "Strong customer authentication required",
+	"200": "Error with bank systems, try again later",
+	"205": "Communication error with bank, try again later",
+	"301": "Error with Realex Payments systems, try again later",
+	"501": "Transaction has already been processed",
+	"502": "Compulsory field not present",
+	"508": "Invalid data in a field",
+	"509": "Invalid card details",
+	"666": "Client deactivated",
+}
+
+// referrals are the 1xx codes which refer the transaction to the bank rather than decline it
+var referrals = map[ResultCode]bool{"102": true, "103": true}
+
+// Class is the group the result code belongs to
+func (c ResultCode) Class() ResultClass {
+	switch {
+	case c == ResultSuccess:
+		return ResultClassSuccess
+	case c == ResultPending:
+		return ResultClassPending
+	case c == "666":
+		return ResultClassClientDeactivated
+	case len(c) != 3:
+		return ResultClassUnknown
+	case referrals[c]:
+		return ResultClassReferral
+	}
+
+	switch c[0] {
+	case '1':
+		return ResultClassDeclined
+	case '2':
+		return ResultClassBankError
+	case '3':
+		return ResultClassGatewayError
+	case '5':
+		return ResultClassValidationError
+	}
+
+	return ResultClassUnknown
+}
+
+// Message describes the result code, known codes have their own message and others that of their class
+func (c ResultCode) Message() string {
+	if m, ok := resultMessages[c]; ok {
+		return m
+	}
+
+	return c.Class().String()
+}
+
+// IsSuccess is true for successful transactions
+func (c ResultCode) IsSuccess() bool {
+	return c.Class() == ResultClassSuccess
+}
+
+// IsPending is true for alternative payment methods whose outcome will be confirmed later
+func (c ResultCode) IsPending() bool {
+	return c.Class() == ResultClassPending
+}
+
+// IsFinal is true for any outcome which will not change, i.e. any result other than pending
+func (c ResultCode) IsFinal() bool {
+	return !c.IsPending()
+}
+
+// IsDeclined is true for transactions declined by the bank, including referrals
+func (c ResultCode) IsDeclined() bool {
+	class := c.Class()
+	return class == ResultClassDeclined || class == ResultClassReferral
+}
+
+// IsReferral is true for transactions referred to the bank
+func (c ResultCode) IsReferral() bool {
+	return c.Class() == ResultClassReferral
+}
+
+// IsRetryable is true for bank and Realex Payments errors, which may succeed if sent again later
+func (c ResultCode) IsRetryable() bool {
+	class := c.Class()
+	return class == ResultClassBankError || class == ResultClassGatewayError
+}
+
+// IsMerchantConfigError is true for validation errors and deactivated clients, which will fail
+// until the integration or account is fixed
+func (c ResultCode) IsMerchantConfigError() bool {
+	class := c.Class()
+	return class == ResultClassValidationError || class == ResultClassClientDeactivated
+}
+
+// ResultCode is the typed RESULT of the response
+func (r *Response) ResultCode() ResultCode {
+	return ResultCode(r.Result)
+}
+
+// ResultClass is the group the RESULT of the response belongs to
+func (r *Response) ResultClass() ResultClass {
+	return r.ResultCode().Class()
+}
@@ -0,0 +1,55 @@
+package hpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCode(t *testing.T) {
+	var tests = []struct {
+		//given
+		code ResultCode
+
+		//expected
+		class         ResultClass
+		message       string
+		success       bool
+		declined      bool
+		referral      bool
+		retryable     bool
+		merchantError bool
+	}{
+		{"00", ResultClassSuccess, "Successful", true, false, false, false, false},
+		{"101", ResultClassDeclined, "Declined by bank", false, true, false, false, false},
+		{"102", ResultClassReferral, "Referral B, declined by bank", false, true, true, false, false},
+		{"103", ResultClassReferral, "Referral A, card reported lost or stolen", false, true, true, false, false},
+		{"199", ResultClassDeclined, "declined", false, true, false, false, false},
+		{"205", ResultClassBankError, "Communication error with bank, try again later", false, false, false, true, false},
+		{"303", ResultClassGatewayError, "gateway error", false, false, false, true, false},
+		{"508", ResultClassValidationError, "Invalid data in a field", false, false, false, false, true},
+		{"666", ResultClassClientDeactivated, "Client deactivated", false, false, false, false, true},
+		{"", ResultClassUnknown, "unknown", false, false, false, false, false},
+		{"401", ResultClassUnknown, "unknown", false, false, false, false, false},
+	}
+
+	for _, test := range tests {
+		description := "Given result code " + string(test.code)
+
+		assert.Equal(t, test.class, test.code.Class(), description)
+		assert.Equal(t, test.message, test.code.Message(), description)
+		assert.Equal(t, test.success, test.code.IsSuccess(), description)
+		assert.Equal(t, test.declined, test.code.IsDeclined(), description)
+		assert.Equal(t, test.referral, test.code.IsReferral(), description)
+		assert.Equal(t, test.retryable, test.code.IsRetryable(), description)
+		assert.Equal(t, test.merchantError, test.code.IsMerchantConfigError(), description)
+	}
+}
+
+func TestResponseResultClass(t *testing.T) {
+	resp := Response{Result: "102"}
+
+	assert.Equal(t, ResultCode("102"), resp.ResultCode())
+	assert.Equal(t, ResultClassReferral, resp.ResultClass())
+	assert.Equal(t, "referral", resp.ResultClass().String())
+}
@@ -0,0 +1,172 @@
+package hpp
+
+// CVNResult is the result of the Card Verification check
+type CVNResult string
+
+// Card Verification check results
+const (
+	CVNMatched      CVNResult = "M"
+	CVNNotMatched   CVNResult = "N"
+	CVNNotChecked   CVNResult = "I"
+	CVNNotCertified CVNResult = "U"
+	CVNNotProcessed CVNResult = "P"
+)
+
+var cvnDescriptions = map[CVNResult]string{
+	CVNMatched:      "CVV matched",
+	CVNNotMatched:   "CVV not matched",
+	CVNNotChecked:   "CVV not checked due to circumstances",
+	CVNNotCertified: "CVV not checked, issuer not certified",
+	CVNNotProcessed: "CVV not processed",
+}
+
+// Description of the result, empty for unknown results
+func (c CVNResult) Description() string {
+	return cvnDescriptions[c]
+}
+
+// AVSResult is the result of the Address Verification check of the postcode or address
+type AVSResult string
+
+// Address Verification check results
+const (
+	AVSMatched        AVSResult = "M"
+	AVSNotMatched     AVSResult = "N"
+	AVSNotChecked     AVSResult = "I"
+	AVSNotCertified   AVSResult = "U"
+	AVSPartialMatched AVSResult = "P"
+)
+
+var avsDescriptions = map[AVSResult]string{
+	AVSMatched:        "Matched",
+	AVSNotMatched:     "Not matched",
+	AVSNotChecked:     "Not checked due to circumstances",
+	AVSNotCertified:   "Not checked, issuer not certified",
+	AVSPartialMatched: "Partially matched",
+}
+
+// Description of the result, empty for unknown results
+func (a AVSResult) Description() string {
+	return avsDescriptions[a]
+}
+
+// RiskDecision is the outcome of a RiskPolicy
+type RiskDecision int
+
+// Risk decisions, in increasing severity
+const (
+	RiskAccept RiskDecision = iota
+	RiskReview
+	RiskReject
+)
+
+func (d RiskDecision) String() string {
+	switch d {
+	case RiskReview:
+		return "review"
+	case RiskReject:
+		return "reject"
+	}
+
+	return "accept"
+}
+
+// RiskPolicy decides whether to accept, review or reject a transaction from its CVN and AVS results
+// and RealScore. The most severe decision of any check is taken.
+type RiskPolicy struct {
+	// CVN results which reject or send the transaction for review.
+	RejectCVN []CVNResult
+	ReviewCVN []CVNResult
+
+	// AVS postcode results which reject or send the transaction for review.
+	RejectAVSPostcode []AVSResult
+	ReviewAVSPostcode []AVSResult
+
+	// AVS address results which reject or send the transaction for review.
+	RejectAVSAddress []AVSResult
+	ReviewAVSAddress []AVSResult
+
+	// RealScores below these are rejected or sent for review, 0 disables the check.
+	// Responses without a RealScore are not checked.
+	RejectScore int
+	ReviewScore int
+}
+
+// DefaultRiskPolicy rejects CVN mismatches and reviews unchecked CVNs and AVS mismatches,
+// it is used when the HPP has no RiskPolicy
+var DefaultRiskPolicy = RiskPolicy{
+	RejectCVN:         []CVNResult{CVNNotMatched},
+	ReviewCVN:         []CVNResult{CVNNotChecked, CVNNotCertified, CVNNotProcessed},
+	ReviewAVSPostcode: []AVSResult{AVSNotMatched},
+	ReviewAVSAddress:  []AVSResult{AVSNotMatched},
+}
+
+// Decide whether to accept, review or reject the response
+func (p RiskPolicy) Decide(r *Response) RiskDecision {
+	d := RiskAccept
+	raise := func(to RiskDecision) {
+		if to > d {
+			d = to
+		}
+	}
+
+	switch {
+	case containsCVN(p.RejectCVN, r.CvnResult):
+		raise(RiskReject)
+	case containsCVN(p.ReviewCVN, r.CvnResult):
+		raise(RiskReview)
+	}
+
+	switch {
+	case containsAVS(p.RejectAVSPostcode, r.AVSPostcodeResult), containsAVS(p.RejectAVSAddress, r.AVSAddressResult):
+		raise(RiskReject)
+	case containsAVS(p.ReviewAVSPostcode, r.AVSPostcodeResult), containsAVS(p.ReviewAVSAddress, r.AVSAddressResult):
+		raise(RiskReview)
+	}
+
+	if score, ok := r.realScore(); ok {
+		switch {
+		case score < p.RejectScore:
+			raise(RiskReject)
+		case score < p.ReviewScore:
+			raise(RiskReview)
+		}
+	}
+
+	return d
+}
+
+// RiskDecision decides whether to accept, review or reject the response with the RiskPolicy of the HPP
+func (r *Response) RiskDecision() RiskDecision {
+	if r.hpp == nil || r.hpp.RiskPolicy == nil {
+		return DefaultRiskPolicy.Decide(r)
+	}
+
+	return r.hpp.RiskPolicy.Decide(r)
+}
+
+// realScore is the overall RealScore
+func (r *Response) realScore() (int, bool) {
+	score, ok := r.RealScore()
+	return score.Score, ok && score.Score >= 0
+}
+
+func containsCVN(results []CVNResult, c CVNResult) bool {
+	for _, r := range results {
+		if r == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAVS(results []AVSResult, a AVSResult) bool {
+	for _, r := range results {
+		if r == a {
+			return true
+		}
+	}
+
+	return false
+}
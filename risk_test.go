@@ -0,0 +1,89 @@
+package hpp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultDescriptions(t *testing.T) {
+	assert.Equal(t, "CVV matched", CVNMatched.Description())
+	assert.Equal(t, "CVV not checked, issuer not certified", CVNResult("U").Description())
+	assert.Equal(t, "", CVNResult("1").Description())
+	assert.Equal(t, "Partially matched", AVSPartialMatched.Description())
+	assert.Equal(t, "Not matched", AVSResult("N").Description())
+}
+
+func TestRiskPolicyDecide(t *testing.T) {
+	policy := RiskPolicy{
+		RejectCVN:         []CVNResult{CVNNotMatched},
+		ReviewCVN:         []CVNResult{CVNNotChecked},
+		RejectAVSPostcode: []AVSResult{AVSNotMatched},
+		ReviewAVSAddress:  []AVSResult{AVSNotMatched, AVSPartialMatched},
+		RejectScore:       20,
+		ReviewScore:       50,
+	}
+
+	var tests = []struct {
+		//given
+		description string
+		response    Response
+
+		//expected
+		decision RiskDecision
+	}{
+		{"Given matching checks", Response{CvnResult: CVNMatched, AVSPostcodeResult: AVSMatched, AVSAddressResult: AVSMatched}, RiskAccept},
+		{"Given no checks", Response{}, RiskAccept},
+		{"Given a CVN mismatch", Response{CvnResult: CVNNotMatched}, RiskReject},
+		{"Given an unchecked CVN", Response{CvnResult: CVNNotChecked}, RiskReview},
+		{"Given an AVS postcode mismatch", Response{AVSPostcodeResult: AVSNotMatched}, RiskReject},
+		{"Given a partial AVS address match", Response{AVSAddressResult: AVSPartialMatched}, RiskReview},
+		{"Given a review and a reject, the transaction is rejected", Response{CvnResult: CVNNotChecked, AVSPostcodeResult: AVSNotMatched}, RiskReject},
+		{"Given a high RealScore", Response{TSS: map[string]string{"RESULT": "89"}}, RiskAccept},
+		{"Given a RealScore below the review score", Response{TSS: map[string]string{"RESULT": "49"}}, RiskReview},
+		{"Given a RealScore below the reject score", Response{CvnResult: CVNMatched, TSS: map[string]string{"RESULT": "19"}}, RiskReject},
+	}
+
+	for _, test := range tests {
+		// Subject
+		decision := policy.Decide(&test.response)
+
+		// Assertions
+		assert.Equal(t, test.decision, decision, test.description)
+	}
+}
+
+func TestResponseRiskDecision(t *testing.T) {
+	resp := Response{CvnResult: CVNNotChecked}
+	assert.Equal(t, RiskReview, resp.RiskDecision(), "the default policy is used")
+
+	hpp := HPP{RiskPolicy: &RiskPolicy{RejectCVN: []CVNResult{CVNNotChecked}}}
+	resp.hpp = &hpp
+	assert.Equal(t, RiskReject, resp.RiskDecision(), "the policy of the HPP is used")
+	assert.Equal(t, "reject", resp.RiskDecision().String())
+}
+
+func TestResponseAVSResults(t *testing.T) {
+	hpp := New("mysecret")
+
+	resp, err := hpp.FromJSON([]byte(`{
+		"TIMESTAMP": "20130814122239",
+		"MERCHANT_ID": "thestore",
+		"ORDER_ID": "ORD453-11",
+		"RESULT": "00",
+		"MESSAGE": "Successful",
+		"PASREF": "3737468273643",
+		"AUTHCODE": "79347",
+		"SHA1HASH": "f093a0b233daa15f2bf44888f4fe75cb652e7bf0",
+		"CVNRESULT": "M",
+		"AVSPOSTCODERESULT": "M",
+		"AVSADDRESSRESULT": "P"
+	}`), false)
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, CVNMatched, resp.CvnResult)
+		assert.Equal(t, AVSMatched, resp.AVSPostcodeResult)
+		assert.Equal(t, AVSPartialMatched, resp.AVSAddressResult)
+		assert.Empty(t, resp.SupplementaryData)
+	}
+}
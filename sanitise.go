@@ -0,0 +1,125 @@
+package hpp
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitisedField is a free-text field changed by Request.Sanitise
+type SanitisedField struct {
+	// Key is the JSON key of the field, e.g. COMMENT1.
+	Key string
+
+	Original  string
+	Sanitised string
+
+	// Truncated is true if the field was cut to its maximum length.
+	Truncated bool
+}
+
+// transliterations replace characters the free-text fields reject with ones they accept
+var transliterations = map[rune]string{
+	'\u00A0': " ",
+	'‘':      "'", '’': "'", '‚': "'", '′': "'",
+	'“': "\"", '”': "\"", '„': "\"", '″': "\"",
+	'–': "-", '—': "-", '−': "-",
+	'…': "...",
+	'•': "*",
+	'™': "TM",
+	'€': "EUR",
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Æ': "AE",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'Ç': "C", 'ç': "c",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'Ð': "D", 'ð': "d",
+	'Ñ': "N", 'ñ': "n",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O", 'Œ': "OE",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'Ý': "Y", 'Ÿ': "Y", 'ý': "y", 'ÿ': "y",
+	'Þ': "TH", 'þ': "th", 'ß': "ss",
+	'Š': "S", 'š': "s", 'Ž': "Z", 'ž': "z",
+}
+
+// sanitiser cleans a free-text field to match its pattern and length
+type sanitiser struct {
+	key   string
+	field *string
+	re    *regexp.Regexp
+	max   int
+}
+
+func (r *Request) sanitisers() []sanitiser {
+	return []sanitiser{
+		{"COMMENT1", &r.CommentOne, commentRegexp, 255},
+		{"COMMENT2", &r.CommentTwo, commentRegexp, 255},
+		{"CUST_NUM", &r.CustomerNumber, referenceRegexp, 50},
+		{"VAR_REF", &r.VariableReference, referenceRegexp, 50},
+		{"PROD_ID", &r.ProductID, referenceRegexp, 50},
+		{"CARD_PAYMENT_BUTTON", &r.CardPaymentButton, cardPaymentButtonRegexp, 25},
+	}
+}
+
+// Sanitise cleans the free-text fields (comments, customer number, variable reference, product ID
+// and card payment button text) so they pass validation. Characters the field does not accept are
+// transliterated (e.g. smart quotes to straight quotes, é to e) or removed, and the field is
+// truncated to its maximum length. The changed fields are returned.
+func (r *Request) Sanitise() []SanitisedField {
+	var changed []SanitisedField
+	for _, s := range r.sanitisers() {
+		if f, ok := s.sanitise(); ok {
+			changed = append(changed, f)
+		}
+	}
+
+	return changed
+}
+
+func (s sanitiser) sanitise() (SanitisedField, bool) {
+	original := *s.field
+	if original == "" {
+		return SanitisedField{}, false
+	}
+
+	var b strings.Builder
+	for _, c := range original {
+		switch {
+		case s.accepts(string(c)):
+			b.WriteRune(c)
+		case s.accepts(transliterations[c]):
+			b.WriteString(transliterations[c])
+		}
+	}
+
+	sanitised, truncated := truncate(b.String(), s.max)
+	if sanitised == original {
+		return SanitisedField{}, false
+	}
+
+	*s.field = sanitised
+
+	return SanitisedField{Key: s.key, Original: original, Sanitised: sanitised, Truncated: truncated}, true
+}
+
+func (s sanitiser) accepts(str string) bool {
+	return str != "" && s.re.MatchString(str)
+}
+
+// truncate cuts the string to at most max bytes, as validated, without splitting a character
+func truncate(s string, max int) (string, bool) {
+	if len(s) <= max {
+		return s, false
+	}
+
+	s = s[:max]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return s, true
+}
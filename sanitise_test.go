@@ -0,0 +1,82 @@
+package hpp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestSanitise(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		request     Request
+
+		//expected
+		sanitised Request
+		changed   []SanitisedField
+	}{
+		{
+			"Given valid free-text fields, nothing is changed",
+			Request{CommentOne: "Order for “James” – thanks", ProductID: "SKU-1"},
+
+			Request{CommentOne: "Order for “James” – thanks", ProductID: "SKU-1"},
+			nil,
+		},
+		{
+			"Given emoji and smart quotes, they are removed or transliterated",
+			Request{CommentOne: "Great gig 🎸", ProductID: "Beyoncé’s Tour…", CardPaymentButton: "Pay ‘now’"},
+
+			Request{CommentOne: "Great gig ", ProductID: "Beyonces Tour...", CardPaymentButton: "Pay 'now'"},
+			[]SanitisedField{
+				{Key: "COMMENT1", Original: "Great gig 🎸", Sanitised: "Great gig "},
+				{Key: "PROD_ID", Original: "Beyoncé’s Tour…", Sanitised: "Beyonces Tour..."},
+				{Key: "CARD_PAYMENT_BUTTON", Original: "Pay ‘now’", Sanitised: "Pay 'now'"},
+			},
+		},
+		{
+			"Given fields that are too long, they are truncated",
+			Request{CustomerNumber: strings.Repeat("a", 60), CommentTwo: strings.Repeat("é", 200)},
+
+			Request{CustomerNumber: strings.Repeat("a", 50), CommentTwo: strings.Repeat("é", 127)},
+			[]SanitisedField{
+				{Key: "COMMENT2", Original: strings.Repeat("é", 200), Sanitised: strings.Repeat("é", 127), Truncated: true},
+				{Key: "CUST_NUM", Original: strings.Repeat("a", 60), Sanitised: strings.Repeat("a", 50), Truncated: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		r := test.request
+		changed := r.Sanitise()
+
+		// Assertions
+		assert.Equal(t, test.sanitised, r, test.description)
+		assert.Equal(t, test.changed, changed, test.description)
+	}
+}
+
+func TestToJSONSanitise(t *testing.T) {
+	req := testRequest(false, false, false)
+	req.CommentOne = "Thanks 🙂"
+
+	h := New("mysecret")
+	_, err := h.ToJSON(req, false)
+	assert.NotNil(t, err, "requests are not sanitised by default")
+
+	logger := &testLogger{}
+	h = HPP{Secret: "mysecret", Sanitise: true, Logger: logger}
+	js, err := h.ToJSON(req, false)
+
+	assert.Nil(t, err)
+	assert.Contains(t, string(js), `"COMMENT1":"Thanks "`)
+	assert.Contains(t, logger.entries, testLogEntry{"info", "Request field sanitised.", Fields{
+		"merchant_id": "thestore",
+		"order_id":    "ORD453-11",
+		"stage":       StageSanitise,
+		"field":       "COMMENT1",
+		"truncated":   false,
+	}})
+}
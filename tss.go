@@ -0,0 +1,144 @@
+package hpp
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// tssResultKey is the key of the overall RealScore in the TSS
+const tssResultKey = "RESULT"
+
+// tssCheckPrefix prefixes the ID of an individual check, e.g. TSS_1032
+const tssCheckPrefix = "TSS_"
+
+// TSSCheck is the score of an individual RealScore check
+type TSSCheck struct {
+	ID    int
+	Score int
+}
+
+// Description of the check, registered with RegisterTSSCheck
+func (c TSSCheck) Description() string {
+	return TSSCheckDescription(c.ID)
+}
+
+// RealScore is the Transaction Suitability Score of a transaction, broken down into the
+// individual checks requested in RealControl
+type RealScore struct {
+	// Score is the overall RealScore, -1 if only individual checks were returned.
+	Score int
+
+	// Checks are the individual check scores, ordered by ID.
+	Checks []TSSCheck
+}
+
+// Check finds the score of an individual check by its ID
+func (s RealScore) Check(id int) (TSSCheck, bool) {
+	for _, c := range s.Checks {
+		if c.ID == id {
+			return c, true
+		}
+	}
+
+	return TSSCheck{}, false
+}
+
+var (
+	tssChecksMu sync.RWMutex
+
+	// tssChecks describe the individual checks. The checks and their IDs are configured per
+	// merchant in RealControl and Realex publish no fixed list, so none are described until
+	// registered.
+	tssChecks = map[int]string{}
+)
+
+// RegisterTSSCheck adds or replaces the description of an individual RealScore check.
+// Check IDs are specific to the merchant's RealControl configuration, there are no built-in
+// descriptions.
+func RegisterTSSCheck(id int, description string) {
+	tssChecksMu.Lock()
+	defer tssChecksMu.Unlock()
+
+	tssChecks[id] = description
+}
+
+// TSSCheckDescription is the registered description of an individual RealScore check, empty if not registered
+func TSSCheckDescription(id int) string {
+	tssChecksMu.RLock()
+	defer tssChecksMu.RUnlock()
+
+	return tssChecks[id]
+}
+
+// RealScore parses the TSS of the response, combining the overall RESULT and TSS_nnnn checks of the
+// nested TSS with any TSS_nnnn checks received as top-level fields in the supplementary data.
+// Scores which are not integers are ignored. ok is false if the response has no RealScore.
+func (r *Response) RealScore() (score RealScore, ok bool) {
+	score.Score = -1
+	checks := map[int]int{}
+
+	for k, v := range r.TSS {
+		if k == tssResultKey {
+			if n, err := strconv.Atoi(v); err == nil {
+				score.Score = n
+			}
+			continue
+		}
+
+		addTSSCheck(checks, k, v)
+	}
+
+	for k, v := range r.SupplementaryData {
+		switch val := v.(type) {
+		case string:
+			addTSSCheck(checks, k, val)
+		case float64:
+			addTSSCheck(checks, k, strconv.FormatFloat(val, 'f', -1, 64))
+		}
+	}
+
+	for id, s := range checks {
+		score.Checks = append(score.Checks, TSSCheck{ID: id, Score: s})
+	}
+	sort.Slice(score.Checks, func(i, j int) bool { return score.Checks[i].ID < score.Checks[j].ID })
+
+	return score, score.Score >= 0 || len(score.Checks) > 0
+}
+
+func addTSSCheck(checks map[int]int, key, value string) {
+	if !strings.HasPrefix(key, tssCheckPrefix) {
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(key, tssCheckPrefix))
+	if err != nil {
+		return
+	}
+
+	score, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+
+	checks[id] = score
+}
+
+// unmarshalTSS reads the TSS as a nested object of scores, or as the overall RealScore
+// when it is posted as a single field
+func unmarshalTSS(data json.RawMessage) (map[string]string, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var result string
+	if json.Unmarshal(data, &result) == nil {
+		return map[string]string{tssResultKey: result}, nil
+	}
+
+	tss := map[string]string{}
+	err := json.Unmarshal(data, &tss)
+	return tss, err
+}
@@ -0,0 +1,121 @@
+package hpp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseRealScore(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		json        string
+
+		//expected
+		score RealScore
+		ok    bool
+	}{
+		{
+			"Given a nested TSS",
+			`{"TSS": {"RESULT": "89", "TSS_1032": "9", "TSS_1000": "-5"}}`,
+
+			RealScore{Score: 89, Checks: []TSSCheck{{ID: 1000, Score: -5}, {ID: 1032, Score: 9}}},
+			true,
+		},
+		{
+			"Given top-level checks",
+			`{"TSS": {"RESULT": "89"}, "TSS_1032": "9", "TSS_1001": 3, "TSS_OTHER": "1"}`,
+
+			RealScore{Score: 89, Checks: []TSSCheck{{ID: 1001, Score: 3}, {ID: 1032, Score: 9}}},
+			true,
+		},
+		{
+			"Given the overall score as a single field",
+			`{"TSS": "75", "TSS_1032": "9"}`,
+
+			RealScore{Score: 75, Checks: []TSSCheck{{ID: 1032, Score: 9}}},
+			true,
+		},
+		{
+			"Given only checks",
+			`{"TSS": {"TSS_1032": "9"}}`,
+
+			RealScore{Score: -1, Checks: []TSSCheck{{ID: 1032, Score: 9}}},
+			true,
+		},
+		{
+			"Given scores which are not integers",
+			`{"TSS": {"TSS_1": "TSS_1_VALUE", "TSS_2": "TSS_2_VALUE"}}`,
+
+			RealScore{Score: -1},
+			false,
+		},
+		{
+			"Given no TSS",
+			`{}`,
+
+			RealScore{Score: -1},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		var resp Response
+		err := json.Unmarshal([]byte(test.json), &resp)
+		if !assert.Nil(t, err, test.description) {
+			continue
+		}
+
+		// Subject
+		score, ok := resp.RealScore()
+
+		// Assertions
+		assert.Equal(t, test.ok, ok, test.description)
+		assert.Equal(t, test.score, score, test.description)
+	}
+}
+
+func TestRealScoreCheck(t *testing.T) {
+	RegisterTSSCheck(1032, "Card used in multiple countries")
+	defer func() {
+		tssChecksMu.Lock()
+		delete(tssChecks, 1032)
+		tssChecksMu.Unlock()
+	}()
+
+	score := RealScore{Score: 89, Checks: []TSSCheck{{ID: 1032, Score: 9}}}
+
+	check, ok := score.Check(1032)
+	assert.True(t, ok)
+	assert.Equal(t, 9, check.Score)
+	assert.Equal(t, "Card used in multiple countries", check.Description())
+
+	_, ok = score.Check(1000)
+	assert.False(t, ok)
+	assert.Equal(t, "", TSSCheckDescription(1000))
+}
+
+func TestFromRequestFlatTSS(t *testing.T) {
+	hpp := New("mysecret")
+	form := testResponseForm(false)
+	form.Set("TSS", "89")
+	form.Set("TSS_1032", "9")
+
+	req := httptest.NewRequest(http.MethodPost, "/response", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Subject
+	resp, err := hpp.FromRequest(req)
+
+	// Assertions
+	if assert.Nil(t, err) {
+		score, ok := resp.RealScore()
+		assert.True(t, ok)
+		assert.Equal(t, RealScore{Score: 89, Checks: []TSSCheck{{ID: 1032, Score: 9}}}, score)
+	}
+}
@@ -24,10 +24,19 @@ var (
 	languageRegexp          = regexp.MustCompile(`^[a-zA-Z]{2}$|^[a-zA-Z]{0}$`)
 	payerRegexp             = regexp.MustCompile(`^[A-Za-z0-9\_\-\\ ]*$`)
 	payRefRegexp            = regexp.MustCompile(`^[A-Za-z0-9\_\-]*$`)
+	emailRegexp             = regexp.MustCompile(`^([a-zA-Z0-9!#$%&'*+/=?^_{|}~\-]+(\.[a-zA-Z0-9!#$%&'*+/=?^_{|}~\-]+)*@[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+)?$`)
+	mobilePhoneRegexp       = regexp.MustCompile(`^([0-9]{1,3}\|[0-9]{1,15})?$`)
+	addressRegexp           = regexp.MustCompile(`^[\x{0020}-\x{007E}\x{00A0}-\x{00FF}]*$`)
+	postalCodeRegexp        = regexp.MustCompile(`^[a-zA-Z0-9\- ]*$`)
+	countryCodeRegexp       = regexp.MustCompile(`^([0-9]{3})?$`)
+	paymentMethodsRegexp    = regexp.MustCompile(`^([a-zA-Z0-9_\-]+(\|[a-zA-Z0-9_\-]+)*)?$`)
+	urlRegexp               = regexp.MustCompile(`^(https?://[^\s]+)?$`)
 	cardPaymentButtonRegexp = regexp.MustCompile(`^[\x{00C0}\x{00C1}\x{00C2}\x{00C3}\x{00C4}\x{00C5}\x{00C6}\x{00C7}\x{00C8}\x{00C9}\x{00CA}\x{00CB}\x{00CC}\x{00CD}\x{00CE}\x{00CF}\x{00D0}\x{00D1}\x{00D2}\x{00D3}\x{00D4}\x{00D5}\x{00D6}\x{00D7}\x{00D8}\x{00D9}\x{00DA}\x{00DB}\x{00DC}\x{00DD}\x{00DE}\x{00DF}\x{00E0}\x{00E1}\x{00E2}\x{00E3}\x{00E4}\x{00E5}\x{00E6}\x{00E7}\x{00E8}\x{00E9}\x{00EA}\x{00EB}\x{00EC}\x{00ED}\x{00EE}\x{00EF}\x{00F0}\x{00F1}\x{00F2}\x{00F3}\x{00F4}\x{00F5}\x{00F6}\x{00F7}\x{00F8}\x{00A4}\x{00F9}\x{00FA}\x{00FB}\x{00FC}\x{00FD}\x{00FE}\x{00FF}\x{0152}\x{017D}\x{0161}\x{0153}\x{017E}\x{0178}\x{00A5}a-zA-Z0-9\'\,\+\x{0022}\.\_\-\&\/\@\!\?\%\()\*\:\x{00A3}\$\&\x{20AC}\#\[\]\|\=\\\x{201C}\x{201D}\x{201C} ]*$`)
 )
 
 const (
+	requiredMessage = "is required"
+
 	merchantIDSize    = "Merchant ID is required and must be between 1 and 50 characters"
 	merchantIDPattern = "Merchant ID must only contain alphanumeric characters"
 
@@ -42,12 +51,14 @@ const (
 
 	currencySize    = "Currency is required and must be 3 characters in length"
 	currencyPattern = "Currency must only consist of alphabetic characters"
+	currencyUnknown = "Currency must be an ISO 4217 currency code"
 
 	timestampSize    = "Time stamp is required and must be 14 characters in length"
 	timestampPattern = "Time stamp must be in YYYYMMDDHHMMSS format"
 
-	hashSize    = "Security hash must be 40 characters in length"
-	hashPattern = "Security hash must only contain numeric and a-f characters"
+	hashSize       = "Security hash must be 40 characters in length"
+	hashSHA256Size = "Security hash must be 64 characters in length"
+	hashPattern    = "Security hash must only contain numeric and a-f characters"
 
 	autoSettleFlagPattern = "Auto settle flag must be 0, 1, on, off or multi"
 
@@ -61,12 +72,14 @@ const (
 
 	shippingCountrySize    = "Shipping country must not contain more than 50 characters"
 	shippingCountryPattern = "Shipping country must only contain the characters A-Z a-z 0-9 , . -"
+	shippingCountryUnknown = "Shipping country must be an ISO 3166-1 alpha-2 country code"
 
 	billingCodeSize    = "Billing code must not be more than 60 characters in length"
 	billingCodePattern = "Billing code must be of format <digits from postcode>|<digits from address> and contain only a-z A-Z 0-9 , . - / | spaces"
 
 	billingCountrySize    = "Billing country must not contain more than 50 characters"
 	billingCountryPattern = "Billing country must only contain the characters A-Z a-z 0-9 , . -"
+	billingCountryUnknown = "Billing country must be an ISO 3166-1 alpha-2 country code"
 
 	customerNumberSize    = "Customer number must not contain more than 50 characters"
 	customerNumberPattern = "Customer number must only contain the characters a-z A-Z 0-9 - _ . , + @ spaces"
@@ -102,199 +115,337 @@ const (
 
 	dccEnableSize    = "DCC enable flag must not be more than 1 character in length"
 	dccEnablePattern = "DCC enable flag must be 1 or 0"
+
+	customerEmailSize    = "Customer email must not be more than 254 characters in length"
+	customerEmailPattern = "Customer email must be a valid email address"
+
+	customerMobilePhoneSize    = "Customer mobile phone number must not be more than 19 characters in length"
+	customerMobilePhonePattern = "Customer mobile phone number must be of format <country code>|<number> and only contain digits"
+
+	streetSize    = "Street must not be more than 50 characters in length"
+	streetPattern = "Street must only contain printable Latin-1 characters"
+
+	citySize    = "City must not be more than 40 characters in length"
+	cityPattern = "City must only contain printable Latin-1 characters"
+
+	postalCodeSize    = "Postal code must not be more than 16 characters in length"
+	postalCodePattern = "Postal code must only contain the characters a-z A-Z 0-9 - spaces"
+
+	countryCodePattern = "Country code must be an ISO 3166-1 numeric code of 3 digits"
+	countryCodeUnknown = "Country code must be a known ISO 3166-1 numeric country code"
+
+	addressMatchIndicatorPattern = "Address match indicator must be TRUE or FALSE"
+
+	challengeRequestIndicatorPattern = "Challenge request indicator must be NO_PREFERENCE, NO_CHALLENGE_REQUESTED, CHALLENGE_PREFERRED or CHALLENGE_MANDATED"
+
+	paymentMethodsSize    = "Payment methods must not be more than 100 characters in length"
+	paymentMethodsPattern = "Payment methods must be a | separated list of methods containing only a-z A-Z 0-9 _ -"
+
+	transactionStatusURLSize    = "Transaction status URL must not be more than 255 characters in length"
+	transactionStatusURLPattern = "Transaction status URL must be an http or https URL"
+
+	merchantResponseURLSize    = "Merchant response URL must not be more than 255 characters in length"
+	merchantResponseURLPattern = "Merchant response URL must be an http or https URL"
 )
 
 func validateMerchantID(merchantID *string) *validation.FieldRules {
 	return validation.Field(
 		merchantID,
-		validation.Required.Error("is required"),
-		validation.Length(1, 50).Error(merchantIDSize),
-		validation.Match(merchantIDRegexp).Error(merchantIDPattern),
+		required(),
+		length(1, 50, merchantIDSize),
+		match(merchantIDRegexp, merchantIDPattern),
 	)
 }
 
 func validateAccount(account *string) *validation.FieldRules {
 	return validation.Field(
 		account,
-		validation.Length(0, 30).Error(accountSize),
-		validation.Match(accountRegexp).Error(accountPattern),
+		length(0, 30, accountSize),
+		match(accountRegexp, accountPattern),
 	)
 }
 
 func validateOrderID(orderID *string) *validation.FieldRules {
 	return validation.Field(
 		orderID,
-		validation.Length(0, 50).Error(orderIDSize),
-		validation.Match(orderIDRegexp).Error(orderIDPattern),
+		length(0, 50, orderIDSize),
+		match(orderIDRegexp, orderIDPattern),
 	)
 }
 
-func validateAmount(amount *int) *validation.FieldRules {
+func validateAmount(amount *int, otb bool) *validation.FieldRules {
+	if otb {
+		return validation.Field(
+			amount,
+			oneOf(amountOTB, 0),
+		)
+	}
+
 	return validation.Field(
 		amount,
-		validation.Required.Error("is required"),
-		validation.Min(1).Error(amountSize),
-		validation.Max(999999999).Error(amountSize),
+		required(),
+		atLeast(1, amountSize),
+		atMost(999999999, amountSize),
 	)
 }
 
 func validateCurrency(currency *string) *validation.FieldRules {
 	return validation.Field(
 		currency,
-		validation.Length(3, 3).Error(currencySize),
-		validation.Match(alphaRegexp).Error(currencyPattern),
+		length(3, 3, currencySize),
+		match(alphaRegexp, currencyPattern),
+		known(isCurrency, currencyUnknown),
 	)
 }
 
-func validateHash(hash *string) *validation.FieldRules {
+func validateHash(hash *string, alg HashAlgorithm) *validation.FieldRules {
+	size := hashSize
+	if alg == SHA256 {
+		size = hashSHA256Size
+	}
+
 	return validation.Field(
 		hash,
-		validation.Length(40, 40).Error(hashSize),
-		validation.Match(hexadecimalRegexp).Error(hashPattern),
+		length(alg.Size(), alg.Size(), size),
+		match(hexadecimalRegexp, hashPattern),
 	)
 }
 
 func validateAutoSettleFlag(autoSettle *string) *validation.FieldRules {
 	return validation.Field(
 		autoSettle,
-		validation.Match(autoSettleFlagRegexp).Error(autoSettleFlagPattern),
+		match(autoSettleFlagRegexp, autoSettleFlagPattern),
 	)
 }
 
 func validateEnableCardStorage(ecs *string) *validation.FieldRules {
 	return validation.Field(
 		ecs,
-		validation.Match(boolRegexp).Error(cardStorageEnablePattern),
+		match(boolRegexp, cardStorageEnablePattern),
 	)
 }
 
 func validateOfferSaveCard(osc *string) *validation.FieldRules {
 	return validation.Field(
 		osc,
-		validation.Match(boolRegexp).Error(offerSaveCardPattern),
+		match(boolRegexp, offerSaveCardPattern),
 	)
 }
 
 func validateValidCardOnly(vco *string) *validation.FieldRules {
 	return validation.Field(
 		vco,
-		validation.Match(boolRegexp).Error(validateCardOnlyPattern),
+		match(boolRegexp, validateCardOnlyPattern),
 	)
 }
 
 func validateDCCEnable(dcce *string) *validation.FieldRules {
 	return validation.Field(
 		dcce,
-		validation.Match(boolRegexp).Error(dccEnablePattern),
+		match(boolRegexp, dccEnablePattern),
 	)
 }
 
 func validateComment(comment *string) *validation.FieldRules {
 	return validation.Field(
 		comment,
-		validation.Length(0, 255).Error(commentSize),
-		validation.Match(commentRegexp).Error(commentPattern),
+		length(0, 255, commentSize),
+		match(commentRegexp, commentPattern),
 	)
 }
 
 func validateReturnTSS(tss *string) *validation.FieldRules {
 	return validation.Field(
 		tss,
-		validation.Match(boolRegexp).Error(returnTssPattern),
+		match(boolRegexp, returnTssPattern),
 	)
 }
 
 func validateShippingCode(shippingCode *string) *validation.FieldRules {
 	return validation.Field(
 		shippingCode,
-		validation.Length(0, 30).Error(shippingCodeSize),
-		validation.Match(shippingCodeRegexp).Error(shippingCodePattern),
+		length(0, shippingCodeMaxLength, shippingCodeSize),
+		match(shippingCodeRegexp, shippingCodePattern),
 	)
 }
 
 func validateShippingCountry(shippingCountry *string) *validation.FieldRules {
 	return validation.Field(
 		shippingCountry,
-		validation.Length(0, 50).Error(shippingCountrySize),
-		validation.Match(countryRegexp).Error(shippingCountryPattern),
+		length(0, 50, shippingCountrySize),
+		match(countryRegexp, shippingCountryPattern),
+		known(isAlpha2Country, shippingCountryUnknown),
 	)
 }
 
 func validateBillingCode(billingCode *string) *validation.FieldRules {
 	return validation.Field(
 		billingCode,
-		validation.Length(0, 60).Error(billingCodeSize),
-		validation.Match(billingCodeRegexp).Error(billingCodePattern),
+		length(0, billingCodeMaxLength, billingCodeSize),
+		match(billingCodeRegexp, billingCodePattern),
 	)
 }
 
 func validateBillingCountry(billingCountry *string) *validation.FieldRules {
 	return validation.Field(
 		billingCountry,
-		validation.Length(0, 50).Error(billingCountrySize),
-		validation.Match(countryRegexp).Error(billingCountryPattern),
+		length(0, 50, billingCountrySize),
+		match(countryRegexp, billingCountryPattern),
+		known(isAlpha2Country, billingCountryUnknown),
 	)
 }
 
 func validateCustomerNumber(customerNumber *string) *validation.FieldRules {
 	return validation.Field(
 		customerNumber,
-		validation.Length(0, 50).Error(customerNumberSize),
-		validation.Match(referenceRegexp).Error(customerNumberPattern),
+		length(0, 50, customerNumberSize),
+		match(referenceRegexp, customerNumberPattern),
 	)
 }
 
 func validateVariableReference(variableReference *string) *validation.FieldRules {
 	return validation.Field(
 		variableReference,
-		validation.Length(0, 50).Error(variableReferenceSize),
-		validation.Match(referenceRegexp).Error(variableReferencePattern),
+		length(0, 50, variableReferenceSize),
+		match(referenceRegexp, variableReferencePattern),
 	)
 }
 
 func validateProductID(productID *string) *validation.FieldRules {
 	return validation.Field(
 		productID,
-		validation.Length(0, 50).Error(productIDSize),
-		validation.Match(referenceRegexp).Error(productIDPattern),
+		length(0, 50, productIDSize),
+		match(referenceRegexp, productIDPattern),
 	)
 }
 
 func validateLanguage(language *string) *validation.FieldRules {
 	return validation.Field(
 		language,
-		validation.Match(languageRegexp).Error(languagePattern),
+		match(languageRegexp, languagePattern),
 	)
 }
 
 func validateCardPaymentButton(cardPaymentButton *string) *validation.FieldRules {
 	return validation.Field(
 		cardPaymentButton,
-		validation.Length(0, 25).Error(cardPaymentButtonTextSize),
-		validation.Match(cardPaymentButtonRegexp).Error(cardPaymentButtonTextPattern),
+		length(0, 25, cardPaymentButtonTextSize),
+		match(cardPaymentButtonRegexp, cardPaymentButtonTextPattern),
 	)
 }
 
 func validatePayerReference(payerReference *string) *validation.FieldRules {
 	return validation.Field(
 		payerReference,
-		validation.Length(0, 50).Error(payerReferenceSize),
-		validation.Match(payerRegexp).Error(payerReferenceSize),
+		length(0, 50, payerReferenceSize),
+		match(payerRegexp, payerReferenceSize),
 	)
 }
 
 func validatePaymentReference(paymentReference *string) *validation.FieldRules {
 	return validation.Field(
 		paymentReference,
-		validation.Length(0, 50).Error(paymentReferenceSize),
-		validation.Match(payRefRegexp).Error(paymentReferencePattern),
+		length(0, 50, paymentReferenceSize),
+		match(payRefRegexp, paymentReferencePattern),
 	)
 }
 
 func validatePayerExists(payerExists *string) *validation.FieldRules {
 	return validation.Field(
 		payerExists,
-		validation.Length(1, 1).Error(payerExistsSize),
-		validation.Match(payerExistsRegexp).Error(payerExistsPattern),
+		length(1, 1, payerExistsSize),
+		match(payerExistsRegexp, payerExistsPattern),
+	)
+}
+
+func validateCustomerEmail(email *string) *validation.FieldRules {
+	return validation.Field(
+		email,
+		length(0, 254, customerEmailSize),
+		match(emailRegexp, customerEmailPattern),
+	)
+}
+
+func validateCustomerMobilePhone(phone *string) *validation.FieldRules {
+	return validation.Field(
+		phone,
+		length(0, 19, customerMobilePhoneSize),
+		match(mobilePhoneRegexp, customerMobilePhonePattern),
+	)
+}
+
+func validateStreet(street *string) *validation.FieldRules {
+	return validation.Field(
+		street,
+		length(0, 50, streetSize),
+		match(addressRegexp, streetPattern),
+	)
+}
+
+func validateCity(city *string) *validation.FieldRules {
+	return validation.Field(
+		city,
+		length(0, 40, citySize),
+		match(addressRegexp, cityPattern),
+	)
+}
+
+func validatePostalCode(postalCode *string) *validation.FieldRules {
+	return validation.Field(
+		postalCode,
+		length(0, 16, postalCodeSize),
+		match(postalCodeRegexp, postalCodePattern),
+	)
+}
+
+func validateCountryCode(countryCode *string) *validation.FieldRules {
+	return validation.Field(
+		countryCode,
+		match(countryCodeRegexp, countryCodePattern),
+		known(isNumericCountry, countryCodeUnknown),
+	)
+}
+
+func validateAddressMatchIndicator(indicator *string) *validation.FieldRules {
+	return validation.Field(
+		indicator,
+		oneOf(addressMatchIndicatorPattern, "TRUE", "FALSE"),
+	)
+}
+
+func validateChallengeRequestIndicator(indicator *string) *validation.FieldRules {
+	return validation.Field(
+		indicator,
+		oneOf(
+			challengeRequestIndicatorPattern,
+			"NO_PREFERENCE",
+			"NO_CHALLENGE_REQUESTED",
+			"CHALLENGE_PREFERRED",
+			"CHALLENGE_MANDATED",
+		),
+	)
+}
+
+func validatePaymentMethods(methods *string) *validation.FieldRules {
+	return validation.Field(
+		methods,
+		length(0, 100, paymentMethodsSize),
+		match(paymentMethodsRegexp, paymentMethodsPattern),
+	)
+}
+
+func validateTransactionStatusURL(url *string) *validation.FieldRules {
+	return validation.Field(
+		url,
+		length(0, 255, transactionStatusURLSize),
+		match(urlRegexp, transactionStatusURLPattern),
+	)
+}
+
+func validateMerchantResponseURL(url *string) *validation.FieldRules {
+	return validation.Field(
+		url,
+		length(0, 255, merchantResponseURLSize),
+		match(urlRegexp, merchantResponseURLPattern),
 	)
 }
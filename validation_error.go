@@ -0,0 +1,162 @@
+package hpp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-ozzo/ozzo-validation"
+)
+
+// Rule codes reported by a FieldError
+const (
+	CodeRequired   = "required"
+	CodeTooShort   = "too_short"
+	CodeTooLong    = "too_long"
+	CodeTooSmall   = "too_small"
+	CodeTooLarge   = "too_large"
+	CodeBadPattern = "bad_pattern"
+	CodeUnknown    = "unknown"
+	CodeNotAllowed = "not_allowed"
+)
+
+// FieldError is a failing field of a request
+type FieldError struct {
+	// Key is the JSON key of the field, e.g. ORDER_ID.
+	Key string `json:"key"`
+
+	// Code is the rule which failed, e.g. too_long.
+	Code string `json:"code"`
+
+	// Limit is the length or value limit of the rule, 0 for rules without a limit.
+	Limit int `json:"limit,omitempty"`
+
+	// Message describes the failure.
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationError is returned by Request.Validate with every failing field, ordered by key
+type ValidationError struct {
+	Fields []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	s := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		s[i] = fmt.Sprintf("%s: %s", f.Key, f.Message)
+	}
+
+	return strings.Join(s, "; ") + "."
+}
+
+// Field finds the error for a JSON key
+func (e *ValidationError) Field(key string) (FieldError, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+
+	return FieldError{}, false
+}
+
+// newValidationError converts the errors from validation.ValidateStruct into a ValidationError,
+// any other error is returned as is
+func newValidationError(err error) error {
+	errs, ok := err.(validation.Errors)
+	if !ok || len(errs) == 0 {
+		return err
+	}
+
+	keys := make([]string, 0, len(errs))
+	for k := range errs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ve := &ValidationError{}
+	for _, k := range keys {
+		fe, ok := errs[k].(FieldError)
+		if !ok {
+			fe = FieldError{Message: errs[k].Error()}
+		}
+		fe.Key = k
+
+		ve.Fields = append(ve.Fields, fe)
+	}
+
+	return ve
+}
+
+// codedRule reports the failures of a rule as a FieldError with its code and limit
+type codedRule struct {
+	rule  validation.Rule
+	code  string
+	limit int
+}
+
+func (c codedRule) Validate(value interface{}) error {
+	err := c.rule.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(validation.InternalError); ok {
+		return err
+	}
+
+	return FieldError{Code: c.code, Limit: c.limit, Message: err.Error()}
+}
+
+// lengthRule reports too_short or too_long depending on which bound the value is outside of
+type lengthRule struct {
+	min, max int
+	message  string
+}
+
+func (l lengthRule) Validate(value interface{}) error {
+	err := validation.Length(l.min, l.max).Error(l.message).Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	value, _ = validation.Indirect(value)
+	if n, lerr := validation.LengthOfValue(value); lerr == nil && n < l.min {
+		return FieldError{Code: CodeTooShort, Limit: l.min, Message: err.Error()}
+	}
+
+	return FieldError{Code: CodeTooLong, Limit: l.max, Message: err.Error()}
+}
+
+func required() validation.Rule {
+	return codedRule{rule: validation.Required.Error(requiredMessage), code: CodeRequired}
+}
+
+func length(min, max int, message string) validation.Rule {
+	return lengthRule{min: min, max: max, message: message}
+}
+
+func match(re *regexp.Regexp, message string) validation.Rule {
+	return codedRule{rule: validation.Match(re).Error(message), code: CodeBadPattern}
+}
+
+func known(f func(string) bool, message string) validation.Rule {
+	return codedRule{rule: validation.NewStringRule(f, message), code: CodeUnknown}
+}
+
+func atLeast(min int, message string) validation.Rule {
+	return codedRule{rule: validation.Min(min).Error(message), code: CodeTooSmall, limit: min}
+}
+
+func atMost(max int, message string) validation.Rule {
+	return codedRule{rule: validation.Max(max).Error(message), code: CodeTooLarge, limit: max}
+}
+
+func oneOf(message string, values ...interface{}) validation.Rule {
+	return codedRule{rule: validation.In(values...).Error(message), code: CodeNotAllowed}
+}
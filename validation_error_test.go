@@ -0,0 +1,104 @@
+package hpp
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationError(t *testing.T) {
+	var tests = []struct {
+		//given
+		description string
+		request     Request
+
+		//expected
+		fields []FieldError
+	}{
+		{
+			"Given the required attributes are missing",
+			Request{},
+
+			[]FieldError{
+				{Key: "AMOUNT", Code: CodeRequired, Message: requiredMessage},
+				{Key: "MERCHANT_ID", Code: CodeRequired, Message: requiredMessage},
+			},
+		},
+		{
+			"Given attributes that are too long or short",
+			Request{Amount: 1, MerchantID: "test", OrderID: randomString(51), Currency: "EURO", Hash: "abc"},
+
+			[]FieldError{
+				{Key: "CURRENCY", Code: CodeTooLong, Limit: 3, Message: currencySize},
+				{Key: "ORDER_ID", Code: CodeTooLong, Limit: 50, Message: orderIDSize},
+				{Key: "SHA1HASH", Code: CodeTooShort, Limit: 40, Message: hashSize},
+			},
+		},
+		{
+			"Given attributes that do not match their patterns or are unknown",
+			Request{Amount: 1, MerchantID: "test%", BillingCountry: "XX", AddressMatchIndicator: "YES"},
+
+			[]FieldError{
+				{Key: "BILLING_CO", Code: CodeUnknown, Message: billingCountryUnknown},
+				{Key: "HPP_ADDRESS_MATCH_INDICATOR", Code: CodeNotAllowed, Message: addressMatchIndicatorPattern},
+				{Key: "MERCHANT_ID", Code: CodeBadPattern, Message: merchantIDPattern},
+			},
+		},
+		{
+			"Given an amount that is too large",
+			Request{Amount: 1000000000, MerchantID: "test"},
+
+			[]FieldError{
+				{Key: "AMOUNT", Code: CodeTooLarge, Limit: 999999999, Message: amountSize},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// Subject
+		err := test.request.Validate()
+
+		// Assertions
+		var ve *ValidationError
+		if assert.True(t, errors.As(err, &ve), test.description) {
+			assert.Equal(t, test.fields, ve.Fields, test.description)
+		}
+	}
+}
+
+func TestValidationErrorField(t *testing.T) {
+	err := validationError(Request{MerchantID: "test"})
+
+	f, ok := err.Field("AMOUNT")
+	assert.True(t, ok)
+	assert.Equal(t, CodeRequired, f.Code)
+
+	_, ok = err.Field("MERCHANT_ID")
+	assert.False(t, ok)
+}
+
+func TestValidationErrorMarshalJSON(t *testing.T) {
+	err := validationError(Request{Amount: 1, MerchantID: "test", OrderID: randomString(51)})
+
+	js, jerr := json.Marshal(err)
+
+	assert.Nil(t, jerr)
+	assert.JSONEq(t, `{"errors":[{"key":"ORDER_ID","code":"too_long","limit":50,"message":"`+orderIDSize+`"}]}`, string(js))
+}
+
+func TestToJSONValidationError(t *testing.T) {
+	h := New("mysecret")
+
+	_, err := h.ToJSON(Request{MerchantID: "test"}, false)
+
+	var ve *ValidationError
+	assert.True(t, errors.As(err, &ve))
+}
+
+func validationError(r Request) *ValidationError {
+	var ve *ValidationError
+	errors.As(r.Validate(), &ve)
+	return ve
+}